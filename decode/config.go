@@ -0,0 +1,355 @@
+package decode
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Parser parses the content of a config file into a map.
+type Parser func(data []byte) (map[string]interface{}, error)
+
+// Sources maps destination values by Go field path, e.g. "DB.Host", to the layer
+// that set them last: "file:<path>", "env" or "flag". Nested structs and maps are
+// not listed themselves. Fields absent from Sources keep their default value.
+type Sources map[string]string
+
+// Loader decodes config files, environment variables and command-line flags into
+// a struct. Later layers override earlier ones: defaults, files in the order they
+// were added, env, flags.
+type Loader struct {
+	files     []configFile
+	fileTags  []string
+	parsers   map[string]Parser
+	env       bool
+	envPrefix string
+	flags     *flag.FlagSet
+	opts      []Option
+}
+
+type configFile struct {
+	path     string
+	optional bool
+}
+
+// LoaderOption configures a Loader.
+type LoaderOption func(*Loader)
+
+// NewLoader creates a Loader configured by opts. JSON files and key = value files
+// with [section] headers (.ini, .conf, .properties) are parsed out of the box,
+// other formats are added with WithParser.
+func NewLoader(opts ...LoaderOption) *Loader {
+	l := &Loader{
+		fileTags: []string{"json"},
+		parsers: map[string]Parser{
+			".json":       parseJSON,
+			".ini":        parseKeyValue,
+			".conf":       parseKeyValue,
+			".properties": parseKeyValue,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// WithFile adds a config file layer. A missing file is an error.
+func WithFile(path string) LoaderOption {
+	return func(l *Loader) {
+		l.files = append(l.files, configFile{path: path})
+	}
+}
+
+// WithOptionalFile adds a config file layer that is skipped when the file does not exist.
+func WithOptionalFile(path string) LoaderOption {
+	return func(l *Loader) {
+		l.files = append(l.files, configFile{path: path, optional: true})
+	}
+}
+
+// WithParser registers the parser of files with the extension ext, e.g. ".yaml".
+func WithParser(ext string, parser Parser) LoaderOption {
+	return func(l *Loader) {
+		l.parsers[strings.ToLower(ext)] = parser
+	}
+}
+
+// WithFileTags sets the struct tags naming fields in config files, "json" by default.
+func WithFileTags(tags ...string) LoaderOption {
+	return func(l *Loader) {
+		l.fileTags = tags
+	}
+}
+
+// WithEnvPrefix adds the layer of environment variables starting with prefix, decoded like FromEnv.
+func WithEnvPrefix(prefix string) LoaderOption {
+	return func(l *Loader) {
+		l.env = true
+		l.envPrefix = prefix
+	}
+}
+
+// WithFlagSet adds the layer of flags of set given on the command line, decoded like FromFlags.
+func WithFlagSet(set *flag.FlagSet) LoaderOption {
+	return func(l *Loader) {
+		l.flags = set
+	}
+}
+
+// WithDecodeOptions sets the options of every layer, e.g. WithHooks or WithValidate.
+// Tags are set per layer and can't be changed here.
+func WithDecodeOptions(opts ...Option) LoaderOption {
+	return func(l *Loader) {
+		l.opts = append(l.opts, opts...)
+	}
+}
+
+// Load decodes every layer into destination, which must be a pointer to a struct,
+// and reports the layer each field came from. Validation runs once on the result.
+func (l *Loader) Load(destination interface{}) (Sources, error) {
+	sources := make(Sources)
+
+	d := l.fileDecoder()
+	if err := d.Decode(map[string]interface{}{}, destination); err != nil {
+		return nil, err
+	}
+
+	for _, file := range l.files {
+		values, err := l.readFile(file)
+		if err != nil {
+			return nil, err
+		} else if values == nil {
+			continue
+		}
+
+		if err := layer(d, values, destination, "file:"+file.path, sources); err != nil {
+			return nil, fmt.Errorf("%s: %w", file.path, err)
+		}
+	}
+
+	if l.env {
+		d := envDecoder(l.options(WithTags(envTag))...)
+		if err := layer(withoutValidate(d), envValues(l.envPrefix, d.env), destination, "env", sources); err != nil {
+			return nil, fmt.Errorf("env: %w", err)
+		}
+	}
+
+	if l.flags != nil {
+		d := flagDecoder(l.options(WithTags(flagTag))...)
+		if err := layer(withoutValidate(d), flagValues(l.flags, false), destination, "flag", sources); err != nil {
+			return nil, fmt.Errorf("flags: %w", err)
+		}
+	}
+
+	if err := NewDecoder(l.options(WithTags(l.fileTags...))...).validate(destination); err != nil {
+		return nil, err
+	}
+
+	return sources, nil
+}
+
+// fileDecoder returns the decoder of config files. Like every layer it skips
+// validation, which runs once on the merged result.
+func (l *Loader) fileDecoder() *Decoder {
+	opts := append([]Option{WithFlags(DecoderWeaklyTyped | DecoderCaseInsensitive | DecoderMatchConventions)},
+		l.options(WithTags(l.fileTags...), WithKeySeparator("."))...)
+
+	return withoutValidate(NewDecoder(opts...))
+}
+
+// options returns the loader decode options followed by extra.
+func (l *Loader) options(extra ...Option) []Option {
+	opts := make([]Option, 0, len(l.opts)+len(extra))
+
+	return append(append(opts, l.opts...), extra...)
+}
+
+func withoutValidate(d *Decoder) *Decoder {
+	d.flag &^= DecoderValidate
+
+	return d
+}
+
+// layer merges values into destination and records the leaf fields they set in sources.
+func layer(d *Decoder, values interface{}, destination interface{}, name string, sources Sources) error {
+	var md Metadata
+	if err := d.Decode(values, destination, WithMerge(), WithMetadata(&md)); err != nil {
+		return err
+	}
+
+	for _, field := range md.Fields {
+		if isLeafField(reflect.ValueOf(destination), field) {
+			sources[field] = name
+		}
+	}
+
+	return nil
+}
+
+// isLeafField reports whether the field of v at a Go field path such as
+// "Servers[0].Host" holds a value rather than a nested struct or map.
+func isLeafField(v reflect.Value, path string) bool {
+	for _, part := range strings.Split(path, ".") {
+		name, indexes, _ := strings.Cut(part, "[")
+
+		v = indirect(v)
+		if v.Kind() != reflect.Struct {
+			return false
+		}
+
+		sf, ok := v.Type().FieldByName(name)
+		if !ok {
+			return false
+		}
+
+		if v, ok = fieldByIndex(v, sf.Index, false); !ok {
+			return false
+		}
+
+		for indexes != "" {
+			var index string
+			index, indexes, _ = strings.Cut(indexes, "]")
+			indexes = strings.TrimPrefix(indexes, "[")
+
+			i, err := strconv.Atoi(index)
+			if v = indirect(v); err != nil || (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) || i >= v.Len() {
+				return false
+			}
+			v = v.Index(i)
+		}
+	}
+
+	v = indirect(v)
+	if !v.IsValid() {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		return false
+
+	case reflect.Struct:
+		return v.Type() == timeType || reflect.PointerTo(v.Type()).Implements(textUnmarshalerType)
+	}
+
+	return true
+}
+
+// indirect dereferences pointers and interfaces, returning an invalid value for nil ones.
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+
+	return v
+}
+
+// readFile parses a config file, returning nil for a missing optional file.
+func (l *Loader) readFile(file configFile) (map[string]interface{}, error) {
+	parser, ok := l.parsers[strings.ToLower(filepath.Ext(file.path))]
+	if !ok {
+		return nil, fmt.Errorf("%s: no parser for %q files", file.path, filepath.Ext(file.path))
+	}
+
+	data, err := os.ReadFile(file.path)
+	if err != nil {
+		if file.optional && errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	values, err := parser(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file.path, err)
+	}
+
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+
+	return values, nil
+}
+
+// validate checks the validate tag rules of the struct destination points to,
+// including structs in its slices, arrays, maps and pointers.
+func (d *Decoder) validate(destination interface{}) error {
+	state := &decoder{Decoder: *d}
+
+	v := reflect.Indirect(reflect.ValueOf(destination))
+	if d.flag&DecoderValidate == 0 || v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	if err := state.validateValue(v, ""); err != nil {
+		return err
+	}
+
+	return errors.Join(state.errs...)
+}
+
+func parseJSON(data []byte) (map[string]interface{}, error) {
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// parseKeyValue parses lines of key = value. Keys after a [section] header get the
+// section as a dotted prefix, lines starting with # or ; are comments.
+func parseKeyValue(data []byte) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	section := ""
+
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+			continue
+
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: missing =", i+1)
+		}
+
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			if value[0] == '"' {
+				unquoted, err := strconv.Unquote(value)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", i+1, err)
+				}
+				value = unquoted
+			} else {
+				value = value[1 : len(value)-1]
+			}
+		}
+
+		if section != "" {
+			key = section + "." + key
+		}
+		values[key] = value
+	}
+
+	return values, nil
+}
@@ -0,0 +1,212 @@
+package decode
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type loaderConfig struct {
+	Name    string        `json:"name" env:"NAME" flag:"name" validate:"required"`
+	Port    int           `json:"port" env:"PORT" flag:"port" default:"8080"`
+	Timeout time.Duration `json:"timeout" env:"TIMEOUT" default:"5s"`
+	Debug   bool          `json:"debug" env:"DEBUG" flag:"debug"`
+	Peers   []string      `json:"peers" env:"PEERS"`
+	DB      struct {
+		Host     string `json:"host" env:"HOST" flag:"host" default:"localhost"`
+		MaxConns int    `json:"max_conns"`
+	} `json:"db" env:"DB" flag:"db"`
+}
+
+func writeFile(t *testing.T, name string, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoader(t *testing.T) {
+	base := writeFile(t, "base.json", `{"name": "api", "port": 9000, "peers": ["a"], "db": {"host": "db", "max_conns": 5}}`)
+	local := writeFile(t, "local.ini", "debug = true\n# comment\n[db]\nmax_conns = \"10\"\n")
+
+	t.Run("test layers", func(t *testing.T) {
+		set := flag.NewFlagSet("test", flag.ContinueOnError)
+		set.Int("port", 0, "")
+		set.String("db.host", "ignored", "")
+		set.Bool("debug", false, "")
+		if err := set.Parse([]string{"-port=7000"}); err != nil {
+			t.Fatal(err)
+		}
+
+		loader := NewLoader(
+			WithFile(base),
+			WithFile(local),
+			WithOptionalFile(filepath.Join(t.TempDir(), "missing.json")),
+			WithEnvPrefix("APP"),
+			WithFlagSet(set),
+			WithDecodeOptions(WithEnv(map[string]string{"APP_DB_HOST": "env-db", "APP_PEERS": "b,c"}), WithValidate()),
+		)
+
+		testOut := loaderConfig{}
+		sources, err := loader.Load(&testOut)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+
+		want := loaderConfig{Name: "api", Port: 7000, Timeout: 5 * time.Second, Debug: true, Peers: []string{"b", "c"}}
+		want.DB.Host = "env-db"
+		want.DB.MaxConns = 10
+
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("Load() = %+v, want %+v", testOut, want)
+		}
+
+		wantSources := Sources{
+			"Name":        "file:" + base,
+			"Port":        "flag",
+			"Debug":       "file:" + local,
+			"Peers":       "env",
+			"DB.Host":     "env",
+			"DB.MaxConns": "file:" + local,
+		}
+		if !reflect.DeepEqual(sources, wantSources) {
+			t.Errorf("Load() sources = %v, want %v", sources, wantSources)
+		}
+	})
+
+	t.Run("test defaults and validation", func(t *testing.T) {
+		testOut := loaderConfig{}
+
+		_, err := NewLoader(WithDecodeOptions(WithValidate(), WithEnv(map[string]string{}))).Load(&testOut)
+		if !errors.Is(err, ErrorValidation) {
+			t.Errorf("Load() error = %v, want %v", err, ErrorValidation)
+		}
+
+		if testOut.Port != 8080 || testOut.DB.Host != "localhost" {
+			t.Errorf("Load() = %+v, want defaults", testOut)
+		}
+	})
+
+	t.Run("test nested validation", func(t *testing.T) {
+		type server struct {
+			Host string `json:"host" validate:"required"`
+		}
+
+		type tls struct {
+			Cert string `json:"cert" validate:"required"`
+		}
+
+		type config struct {
+			Servers []server `json:"servers"`
+			TLS     *tls     `json:"tls"`
+		}
+
+		path := writeFile(t, "nested.json", `{"servers": [{"host": ""}], "tls": {"cert": ""}}`)
+		testOut := config{}
+
+		_, err := NewLoader(WithFile(path), WithDecodeOptions(WithValidate(), WithCollectErrors())).Load(&testOut)
+
+		for _, want := range []string{"servers[0].host", "tls.cert"} {
+			if !errors.Is(err, ErrorValidation) || !strings.Contains(err.Error(), want) {
+				t.Errorf("Load() error = %v, want validation error at %s", err, want)
+			}
+		}
+	})
+
+	t.Run("test squashed sources", func(t *testing.T) {
+		type base struct {
+			ID int `json:"id"`
+		}
+
+		type config struct {
+			B    base   `json:",squash"`
+			Name string `json:"name"`
+		}
+
+		path := writeFile(t, "squash.json", `{"id": 3, "name": "api"}`)
+		testOut := config{}
+
+		sources, err := NewLoader(WithFile(path)).Load(&testOut)
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+
+		wantSources := Sources{"B.ID": "file:" + path, "Name": "file:" + path}
+		if testOut.B.ID != 3 || !reflect.DeepEqual(sources, wantSources) {
+			t.Errorf("Load() = %+v, sources = %v, want sources %v", testOut, sources, wantSources)
+		}
+	})
+
+	t.Run("test custom parser", func(t *testing.T) {
+		path := writeFile(t, "config.yaml", "name: yaml")
+		parser := func(data []byte) (map[string]interface{}, error) {
+			key, value, _ := strings.Cut(string(data), ":")
+			return map[string]interface{}{key: strings.TrimSpace(value)}, nil
+		}
+		testOut := loaderConfig{}
+
+		if _, err := NewLoader(WithFile(path), WithParser(".yaml", parser)).Load(&testOut); err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+
+		if testOut.Name != "yaml" {
+			t.Errorf("Load() Name = %q, want yaml", testOut.Name)
+		}
+	})
+
+	t.Run("test errors", func(t *testing.T) {
+		testOut := loaderConfig{}
+
+		if _, err := NewLoader(WithFile(filepath.Join(t.TempDir(), "missing.json"))).Load(&testOut); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Load() error = %v, want %v", err, os.ErrNotExist)
+		}
+
+		if _, err := NewLoader(WithFile(writeFile(t, "config.txt", ""))).Load(&testOut); err == nil {
+			t.Error("Load() error = nil, want unknown extension error")
+		}
+
+		if _, err := NewLoader(WithFile(writeFile(t, "bad.json", `{"port": "abc"}`))).Load(&testOut); !errors.Is(err, ErrorTypeMismatch) {
+			t.Errorf("Load() error = %v, want %v", err, ErrorTypeMismatch)
+		}
+	})
+}
+
+func TestIsLeafField(t *testing.T) {
+	type server struct {
+		Host    string
+		Started time.Time
+		Tags    map[string]string
+	}
+
+	v := reflect.ValueOf(&struct {
+		Servers []server
+		Primary *server
+		Backup  *server
+	}{Servers: []server{{Host: "a"}}, Primary: &server{}})
+
+	for path, want := range map[string]bool{
+		"Servers":            true,
+		"Servers[0]":         false,
+		"Servers[0].Host":    true,
+		"Servers[0].Started": true,
+		"Servers[0].Tags":    false,
+		"Servers[1].Host":    false,
+		"Primary":            false,
+		"Primary.Host":       true,
+		"Backup":             true,
+		"Missing":            false,
+	} {
+		if got := isLeafField(v, path); got != want {
+			t.Errorf("isLeafField(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
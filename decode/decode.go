@@ -2,28 +2,80 @@ package decode
 
 import (
 	"errors"
+	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 	"time"
 )
 
-var (
-	ErrorDstNotFound  = errors.New("destination not found")
-	ErrorDstNotSet    = errors.New("destination not set")
-	ErrorTypeMismatch = errors.New("type mismatch")
-)
-
 type DecoderFlag int
 
 const (
 	DecoderStrongFoundDst    DecoderFlag = 0x1 << iota // Error if not found destination
 	DecoderStrongType                                  // Safe source type or error. Explode inner struct to map in map to map
 	DecoderUnwrapStructToMap                           // Unwrap struct to map
+	DecoderCollectErrors                               // Continue on field errors and return all of them joined
+	DecoderValidate                                    // Check the validate tag rules of destination struct fields
+	DecoderWeaklyTyped                                 // Decode strings from env or query: "a,b" to slice, scalar to slice, "" to zero, "yes"/"on" to bool
+	DecoderStrictNumeric                               // Error on numeric overflow, precision loss and NaN/Inf instead of truncating
+	DecoderCaseInsensitive                             // Match source keys to fields ignoring case
+	DecoderMatchConventions                            // Match snake_case, camelCase, kebab-case and UPPER_CASE keys to untagged fields
+	DecoderMerge                                       // Deep merge nested maps and structs into existing values, skip defaults
+	DecoderSkipZero                                    // Leave the destination unchanged for zero source values
+	DecoderAppendSlices                                // Append source elements to destination slices instead of replacing them
 )
 
+// Decoder holds reusable decoding settings. It is safe for concurrent use, except
+// when created with WithMetadata: every Decode call then fills the same Metadata, so
+// a shared Decoder should get WithMetadata per Decode call instead.
+type Decoder struct {
+	tags  fieldTags
+	flag  DecoderFlag
+	meta  *Metadata
+	hooks *Hooks
+
+	matchName MatchName
+	separator string
+	env       map[string]string
+
+	timeLayouts  []string
+	timeLocation *time.Location
+	timeUnit     time.Duration
+}
+
+// decoder holds the state of a single Decode call.
+type decoder struct {
+	Decoder
+	errs    []error
+	dstPath string // Path of the current destination field by Go field names, tracked with metadata
+}
+
+// NewDecoder creates a Decoder configured by opts.
+//
+//	d := decode.NewDecoder(decode.WithTag("json"), decode.WithStrict())
+//	err := d.Decode(source, &destination)
+func NewDecoder(opts ...Option) *Decoder {
+	d := &Decoder{}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
+}
+
 // Decode копирует данные из источника в назначение, поддерживая различные типы данных
 // (структуры, мапы) и их вложенность, используя теги для сопоставления полей.
-func Decode(source interface{}, destination interface{}, tag string, flag DecoderFlag) error {
+// Ошибки декодирования возвращаются как *DecodeError с путём до поля.
+func Decode(source interface{}, destination interface{}, tag string, flag DecoderFlag, opts ...Option) error {
+	d := Decoder{tags: fieldTags{chain: tag}, flag: flag}
+
+	return d.Decode(source, destination, opts...)
+}
+
+// Decode copies source into destination, which must be a non-nil pointer.
+// Options apply to this call only, e.g. WithMetadata.
+func (d *Decoder) Decode(source interface{}, destination interface{}, opts ...Option) error {
 	var sourceVal reflect.Value
 	var dstVal reflect.Value
 
@@ -47,10 +99,40 @@ func Decode(source interface{}, destination interface{}, tag string, flag Decode
 
 	sourceVal = reflect.Indirect(sourceVal)
 
-	return copyValues(sourceVal, dstVal, tag, flag)
+	state := &decoder{Decoder: *d}
+	for _, opt := range opts {
+		opt(&state.Decoder)
+	}
+
+	err := state.copyValues(sourceVal, dstVal, "")
+
+	if state.meta != nil {
+		state.meta.sort()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(state.errs...)
 }
 
-func copyValues(source reflect.Value, destination reflect.Value, tag string, flag DecoderFlag) error {
+// fail returns err, or records it and returns nil when DecoderCollectErrors is set.
+func (d *decoder) fail(err error) error {
+	if d.flag&DecoderCollectErrors == 0 {
+		return err
+	}
+
+	d.errs = append(d.errs, err)
+
+	return nil
+}
+
+func (d *decoder) copyValues(source reflect.Value, destination reflect.Value, path string) error {
+	if done, err := d.applyHook(&source, destination, path); done || err != nil {
+		return err
+	}
+
 	var srcVal reflect.Value
 	var dstVal reflect.Value
 	sourceIsInterface := source.CanInterface()
@@ -63,12 +145,22 @@ func copyValues(source reflect.Value, destination reflect.Value, tag string, fla
 
 	dstVal = destination
 
+	if !srcVal.IsValid() || (srcVal.Kind() == reflect.Ptr && srcVal.IsNil()) {
+		dstVal.Set(reflect.Zero(destination.Type()))
+		return nil
+	}
+
 	if srcVal.CanAddr() && srcVal.IsNil() {
 		zero := reflect.Zero(destination.Type())
 		dstVal.Set(zero)
 		return nil
 	}
 
+	if d.weakEmpty(srcVal, dstVal) {
+		dstVal.Set(reflect.Zero(dstVal.Type()))
+		return nil
+	}
+
 	sourceIsPtr := srcVal.Kind() == reflect.Ptr
 	dstIsPtr := dstVal.Kind() == reflect.Ptr
 
@@ -77,91 +169,127 @@ func copyValues(source reflect.Value, destination reflect.Value, tag string, fla
 	}
 
 	if dstIsPtr {
+		if dstVal.IsNil() && dstVal.CanSet() {
+			dstVal.Set(reflect.New(dstVal.Type().Elem()))
+		}
 		dstVal = dstVal.Elem()
+
+		if done, err := d.applyHook(&srcVal, dstVal, path); done || err != nil {
+			return err
+		}
+	}
+
+	if done, err := d.collapseList(srcVal, dstVal, path); done || err != nil {
+		return err
+	}
+
+	if done, err := d.decodeTime(srcVal, dstVal, path); done || err != nil {
+		return err
+	}
+
+	if done, err := d.decodeUnmarshaler(srcVal, dstVal, path); done || err != nil {
+		return err
+	}
+
+	if done, err := d.decodeWeak(srcVal, dstVal, path); done || err != nil {
+		return err
 	}
 
 	switch srcVal.Kind() {
 	case reflect.Struct:
 		switch dstVal.Kind() {
 		case reflect.Struct:
-			return copyStructToStruct(srcVal, dstVal, tag, flag)
+			return d.copyStructToStruct(srcVal, dstVal, path)
 
 		case reflect.Map:
-			return copyStructToMap(srcVal, dstVal, tag, flag)
+			return d.copyStructToMap(srcVal, dstVal, path)
 
 		case reflect.Interface:
-			return copyStructToStruct(srcVal, dstVal, tag, flag)
+			return d.copyStructToStruct(srcVal, dstVal, path)
 		}
 
 	case reflect.Map:
 		switch dstVal.Kind() {
 		case reflect.Struct:
-			return copyMapToStruct(srcVal, dstVal, tag, flag)
+			return d.copyMapToStruct(srcVal, dstVal, path)
 
 		case reflect.Map:
-			return copyMapToMap(srcVal, dstVal, flag)
+			return d.copyMapToMap(srcVal, dstVal, path)
 
 		case reflect.Interface:
-			return copyMapToMap(srcVal, dstVal, flag)
+			return d.copyMapToMap(srcVal, dstVal, path)
 		}
 
-	case reflect.Slice:
+	case reflect.Slice, reflect.Array:
 		switch dstVal.Kind() {
-		case reflect.Slice:
-			dstVal.Set(reflect.MakeSlice(dstVal.Type(), srcVal.Len(), srcVal.Cap()))
-
-			for i := 0; i < srcVal.Len(); i++ {
-				val := srcVal.Index(i)
-				for val.Kind() == reflect.Interface {
-					val = val.Elem()
-				}
-
-				val = reflect.Indirect(val)
-
-				if dstVal.Type().Elem() == val.Type() {
-					dstVal.Index(i).Set(val.Convert(dstVal.Type().Elem()))
-				} else {
-					if flag&DecoderStrongType != 0 {
-						return ErrorTypeMismatch
-					}
-
-					if converted, err := convertBasicTypes(val, dstVal.Type().Elem()); err == nil {
-						dstVal.Index(i).Set(converted)
-					} else {
-						return err
-					}
-				}
-			}
-
-			return nil
+		case reflect.Slice, reflect.Array:
+			return d.copySlice(srcVal, dstVal, path)
 
 		case reflect.Interface:
-			dstVal.Set(reflect.MakeSlice(dstVal.Type(), srcVal.Len(), srcVal.Cap()))
-
-			for i := 0; i < srcVal.Len(); i++ {
-				dstVal.Index(i).Set(reflect.Indirect(srcVal.Index(i)))
+			data := reflect.New(srcVal.Type()).Elem()
+			if srcVal.Kind() == reflect.Slice {
+				data.Set(reflect.MakeSlice(srcVal.Type(), srcVal.Len(), srcVal.Len()))
 			}
+			reflect.Copy(data, srcVal)
+			dstVal.Set(data)
 
 			return nil
 		}
 
 	default:
 		if srcVal.Kind() == dstVal.Kind() {
+			if err := d.checkNumeric(srcVal, dstVal.Type()); err != nil {
+				return d.fail(newDecodeError(path, srcVal, dstVal.Type(), ErrorTypeMismatch, err))
+			}
+
 			dstVal.Set(srcVal.Convert(dstVal.Type()))
 			return nil
-		} else if flag&DecoderStrongType == 0 {
-			if converted, err := convertBasicTypes(srcVal, dstVal.Type()); err == nil {
+		} else if d.flag&DecoderStrongType == 0 {
+			if converted, err := d.convert(srcVal, dstVal.Type()); err == nil {
 				dstVal.Set(converted)
 				return nil
 			} else {
-				return ErrorTypeMismatch
+				return d.fail(newDecodeError(path, srcVal, dstVal.Type(), ErrorTypeMismatch, err))
 			}
 		} else {
-			return ErrorTypeMismatch
+			return d.fail(newDecodeError(path, srcVal, dstVal.Type(), ErrorTypeMismatch, nil))
 		}
 	}
 
-	return ErrorTypeMismatch
+	return d.fail(newDecodeError(path, srcVal, dstVal.Type(), ErrorTypeMismatch, nil))
+}
+
+// copySlice decodes every element of a slice or array into the destination slice or array.
+func (d *decoder) copySlice(source reflect.Value, destination reflect.Value, path string) error {
+	length := source.Len()
+	offset := 0
+
+	if destination.Kind() == reflect.Slice && d.flag&DecoderAppendSlices != 0 {
+		offset = destination.Len()
+		destination.Set(reflect.AppendSlice(destination, reflect.MakeSlice(destination.Type(), length, length)))
+	} else if destination.Kind() == reflect.Slice {
+		destination.Set(reflect.MakeSlice(destination.Type(), length, length))
+	} else if length > destination.Len() {
+		return d.fail(newDecodeError(path, source, destination.Type(), ErrorTypeMismatch,
+			fmt.Errorf("%d elements do not fit into array of %d", length, destination.Len())))
+	} else {
+		destination.Set(reflect.Zero(destination.Type()))
+	}
+
+	parent := d.dstPath
+	defer func() { d.dstPath = parent }()
+
+	for i := 0; i < length; i++ {
+		if d.meta != nil {
+			d.dstPath = indexPath(parent, offset+i)
+		}
+
+		if err := d.copyValues(source.Index(i), destination.Index(offset+i), indexPath(path, offset+i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func convertBasicTypes(source reflect.Value, targetType reflect.Type) (reflect.Value, error) {
@@ -176,13 +304,17 @@ func convertBasicTypes(source reflect.Value, targetType reflect.Type) (reflect.V
 	case reflect.String:
 		switch source.Kind() {
 		case reflect.String:
-			return reflect.ValueOf(source.String()), nil
+			return reflect.ValueOf(source.String()).Convert(targetType), nil
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			return reflect.ValueOf(strconv.FormatInt(source.Int(), 10)), nil
+			return reflect.ValueOf(strconv.FormatInt(source.Int(), 10)).Convert(targetType), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return reflect.ValueOf(strconv.FormatUint(source.Uint(), 10)).Convert(targetType), nil
 		case reflect.Float32, reflect.Float64:
-			return reflect.ValueOf(strconv.FormatFloat(source.Float(), 'f', -1, 64)), nil
+			return reflect.ValueOf(strconv.FormatFloat(source.Float(), 'f', -1, 64)).Convert(targetType), nil
+		case reflect.Complex64, reflect.Complex128:
+			return reflect.ValueOf(strconv.FormatComplex(source.Complex(), 'f', -1, 128)).Convert(targetType), nil
 		case reflect.Bool:
-			return reflect.ValueOf(strconv.FormatBool(source.Bool())), nil
+			return reflect.ValueOf(strconv.FormatBool(source.Bool())).Convert(targetType), nil
 		}
 		return reflect.Value{}, ErrorTypeMismatch
 
@@ -190,82 +322,135 @@ func convertBasicTypes(source reflect.Value, targetType reflect.Type) (reflect.V
 		if _, ok := targetType.MethodByName("Nanoseconds"); ok {
 			switch source.Kind() {
 			case reflect.String:
-				d, _ := time.ParseDuration(source.String())
-				return reflect.ValueOf(d).Convert(targetType), nil
+				if d, err := time.ParseDuration(source.String()); err == nil {
+					return reflect.ValueOf(d).Convert(targetType), nil
+				} else if intValue, intErr := strconv.ParseInt(source.String(), 10, 64); intErr == nil {
+					return reflect.ValueOf(intValue).Convert(targetType), nil
+				} else {
+					return reflect.Value{}, err
+				}
 			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 				return reflect.ValueOf(source.Int()).Convert(targetType), nil
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+				return reflect.ValueOf(source.Uint()).Convert(targetType), nil
 			case reflect.Float32, reflect.Float64:
 				return reflect.ValueOf(source.Float()).Convert(targetType), nil
 			}
 		} else {
 			switch source.Kind() {
 			case reflect.String:
-				newValue := reflect.New(targetType)
-
-				if m := newValue.Elem().MethodByName("Parse"); m.IsValid() && m.Type().NumIn() == 1 && m.Type().In(0).Kind() == reflect.String &&
-					m.Type().NumOut() == 1 && m.Type().Out(0) == targetType {
-					res := newValue.Elem().MethodByName("Parse").Call([]reflect.Value{reflect.ValueOf(source.String())})
-					return res[0], nil
-				} else if m := newValue.MethodByName("Parse"); m.IsValid() && m.Type().NumIn() == 1 && m.Type().In(0).Kind() == reflect.String {
-					m.Call([]reflect.Value{reflect.ValueOf(source.String())})
-					return newValue.Elem(), nil
+				if res, ok := callParse(source.String(), targetType); ok {
+					return res, nil
+				} else if intValue, err := strconv.ParseInt(source.String(), 10, 64); err == nil {
+					return reflect.ValueOf(intValue).Convert(targetType), nil
 				} else {
-					if intValue, err := strconv.ParseInt(source.String(), 10, 64); err == nil {
-						return reflect.ValueOf(intValue).Convert(targetType), nil
-					} else {
-						return reflect.Value{}, err
-					}
+					return reflect.Value{}, err
 				}
 			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 				return reflect.ValueOf(source.Int()).Convert(targetType), nil
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+				return reflect.ValueOf(source.Uint()).Convert(targetType), nil
 			case reflect.Float32, reflect.Float64:
 				return reflect.ValueOf(source.Float()).Convert(targetType), nil
+			case reflect.Complex64, reflect.Complex128:
+				if c := source.Complex(); imag(c) == 0 {
+					return reflect.ValueOf(real(c)).Convert(targetType), nil
+				}
 			case reflect.Bool:
-				return reflect.ValueOf(source.Bool()).Convert(targetType), nil
+				return reflect.ValueOf(boolToInt(source.Bool())).Convert(targetType), nil
 			}
 		}
 		return reflect.Value{}, ErrorTypeMismatch
 
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		switch source.Kind() {
+		case reflect.String:
+			if res, ok := callParse(source.String(), targetType); ok {
+				return res, nil
+			} else if uintValue, err := strconv.ParseUint(source.String(), 10, targetType.Bits()); err == nil {
+				return reflect.ValueOf(uintValue).Convert(targetType), nil
+			} else {
+				return reflect.Value{}, err
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if source.Int() < 0 {
+				return reflect.Value{}, overflowError(source, targetType)
+			}
+			return convertUint(source, uint64(source.Int()), targetType)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return convertUint(source, source.Uint(), targetType)
+		case reflect.Float32, reflect.Float64:
+			if f := source.Float(); f < 0 || f >= math.MaxUint64 || math.IsNaN(f) {
+				return reflect.Value{}, overflowError(source, targetType)
+			}
+			return convertUint(source, uint64(source.Float()), targetType)
+		case reflect.Complex64, reflect.Complex128:
+			if c := source.Complex(); imag(c) == 0 {
+				return convertBasicTypes(reflect.ValueOf(real(c)), targetType)
+			}
+		case reflect.Bool:
+			return reflect.ValueOf(boolToInt(source.Bool())).Convert(targetType), nil
+		}
+		return reflect.Value{}, ErrorTypeMismatch
+
 	case reflect.Float32, reflect.Float64:
 		switch source.Kind() {
 		case reflect.String:
-			newValue := reflect.New(targetType)
-
-			if m := newValue.Elem().MethodByName("Parse"); m.IsValid() && m.Type().NumIn() == 1 && m.Type().In(0).Kind() == reflect.String &&
-				m.Type().NumOut() == 1 && m.Type().Out(0) == targetType {
-				res := newValue.Elem().MethodByName("Parse").Call([]reflect.Value{reflect.ValueOf(source.String())})
-				return res[0], nil
-			} else if m := newValue.MethodByName("Parse"); m.IsValid() && m.Type().NumIn() == 1 && m.Type().In(0).Kind() == reflect.String {
-				m.Call([]reflect.Value{reflect.ValueOf(source.String())})
-				return newValue.Elem(), nil
+			if res, ok := callParse(source.String(), targetType); ok {
+				return res, nil
+			} else if floatValue, err := strconv.ParseFloat(source.String(), 64); err == nil {
+				return reflect.ValueOf(floatValue).Convert(targetType), nil
 			} else {
-				if intValue, err := strconv.ParseFloat(source.String(), 64); err == nil {
-					return reflect.ValueOf(intValue).Convert(targetType), nil
-				} else {
-					return newValue.Elem(), err
-				}
+				return reflect.Value{}, err
 			}
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 			return reflect.ValueOf(source.Int()).Convert(targetType), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return reflect.ValueOf(source.Uint()).Convert(targetType), nil
 		case reflect.Float32, reflect.Float64:
 			return reflect.ValueOf(source.Float()).Convert(targetType), nil
+		case reflect.Complex64, reflect.Complex128:
+			if c := source.Complex(); imag(c) == 0 {
+				return reflect.ValueOf(real(c)).Convert(targetType), nil
+			}
 		case reflect.Bool:
-			return reflect.ValueOf(source.Bool()).Convert(targetType), nil
+			return reflect.ValueOf(boolToInt(source.Bool())).Convert(targetType), nil
+		}
+		return reflect.Value{}, ErrorTypeMismatch
+
+	case reflect.Complex64, reflect.Complex128:
+		switch source.Kind() {
+		case reflect.String:
+			if complexValue, err := strconv.ParseComplex(source.String(), targetType.Bits()); err == nil {
+				return reflect.ValueOf(complexValue).Convert(targetType), nil
+			} else {
+				return reflect.Value{}, err
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return reflect.ValueOf(complex(float64(source.Int()), 0)).Convert(targetType), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return reflect.ValueOf(complex(float64(source.Uint()), 0)).Convert(targetType), nil
+		case reflect.Float32, reflect.Float64:
+			return reflect.ValueOf(complex(source.Float(), 0)).Convert(targetType), nil
+		case reflect.Complex64, reflect.Complex128:
+			return reflect.ValueOf(source.Complex()).Convert(targetType), nil
 		}
 		return reflect.Value{}, ErrorTypeMismatch
 
 	case reflect.Bool:
 		switch source.Kind() {
 		case reflect.String:
-			if intValue, err := strconv.ParseBool(source.String()); err == nil {
-				return reflect.ValueOf(intValue).Convert(targetType), nil
+			if boolValue, err := strconv.ParseBool(source.String()); err == nil {
+				return reflect.ValueOf(boolValue).Convert(targetType), nil
 			} else {
 				return reflect.Value{}, err
 			}
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			return reflect.ValueOf(source.Int()).Convert(targetType), nil
+			return reflect.ValueOf(source.Int() != 0).Convert(targetType), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return reflect.ValueOf(source.Uint() != 0).Convert(targetType), nil
 		case reflect.Float32, reflect.Float64:
-			return reflect.ValueOf(source.Float()).Convert(targetType), nil
+			return reflect.ValueOf(source.Float() != 0).Convert(targetType), nil
 		case reflect.Bool:
 			return reflect.ValueOf(source.Bool()).Convert(targetType), nil
 		}
@@ -275,224 +460,296 @@ func convertBasicTypes(source reflect.Value, targetType reflect.Type) (reflect.V
 	return reflect.Value{}, ErrorTypeMismatch
 }
 
-func copyStructToMap(source reflect.Value, destination reflect.Value, tag string, flag DecoderFlag) error {
-	if destination.IsNil() {
-		destination.Set(reflect.MakeMap(destination.Type()))
+// callParse decodes str through the Parse method of targetType, either a value method
+// returning the parsed value or a pointer method filling the receiver.
+func callParse(str string, targetType reflect.Type) (reflect.Value, bool) {
+	newValue := reflect.New(targetType)
+
+	if m := newValue.Elem().MethodByName("Parse"); m.IsValid() && m.Type().NumIn() == 1 && m.Type().In(0).Kind() == reflect.String &&
+		m.Type().NumOut() == 1 && m.Type().Out(0) == targetType {
+		res := m.Call([]reflect.Value{reflect.ValueOf(str)})
+		return res[0], true
+	} else if m := newValue.MethodByName("Parse"); m.IsValid() && m.Type().NumIn() == 1 && m.Type().In(0).Kind() == reflect.String {
+		m.Call([]reflect.Value{reflect.ValueOf(str)})
+		return newValue.Elem(), true
 	}
 
-	typeOfSource := source.Type()
-	for i := 0; i < source.NumField(); i++ {
-		srcField := source.Field(i)
-		var fieldName string
+	return reflect.Value{}, false
+}
 
-		if tag != "" {
-			fieldName = typeOfSource.Field(i).Tag.Get(tag)
-			if fieldName == "" {
-				continue
-			}
-		} else {
-			fieldName = typeOfSource.Field(i).Name
+// hasParse reports whether targetType has a Parse method usable by callParse.
+func hasParse(targetType reflect.Type) bool {
+	for _, t := range []reflect.Type{targetType, reflect.PointerTo(targetType)} {
+		if m, ok := t.MethodByName("Parse"); ok && m.Type.NumIn() == 2 && m.Type.In(1).Kind() == reflect.String {
+			return true
 		}
+	}
 
-		var data reflect.Value
-		if destination.Type().Elem().Kind() == reflect.Interface {
-			if flag&DecoderUnwrapStructToMap != 0 && srcField.Kind() == reflect.Struct {
-				data = reflect.MakeMap(reflect.TypeOf(map[string]interface{}{}))
-			} else if srcField.Kind() == reflect.Interface {
-				if reflect.ValueOf(srcField.Interface()).Kind() == reflect.Ptr {
-					data = reflect.New(reflect.TypeOf(srcField.Interface()).Elem())
-				} else {
-					data = reflect.New(reflect.TypeOf(srcField.Interface())).Elem()
-				}
-			} else if srcField.Kind() == reflect.Ptr {
-				data = reflect.New(srcField.Elem().Type())
-			} else {
-				data = reflect.New(srcField.Type()).Elem()
-			}
-		} else {
-			data = reflect.New(destination.Type().Elem()).Elem()
-		}
+	return false
+}
 
-		err := copyValues(srcField, data, tag, flag)
-		if err != nil {
-			return err
-		}
+// convertUint converts value into the unsigned targetType, failing on overflow.
+func convertUint(source reflect.Value, value uint64, targetType reflect.Type) (reflect.Value, error) {
+	if reflect.Zero(targetType).OverflowUint(value) {
+		return reflect.Value{}, overflowError(source, targetType)
+	}
+
+	return reflect.ValueOf(value).Convert(targetType), nil
+}
 
-		destination.SetMapIndex(reflect.ValueOf(fieldName), data)
+func overflowError(source reflect.Value, targetType reflect.Type) error {
+	return fmt.Errorf("%w: %v does not fit into %s", ErrorOverflow, source.Interface(), targetType)
+}
+
+// keyString returns a map key as a path element.
+func keyString(key reflect.Value) string {
+	if key.Kind() == reflect.String {
+		return key.String()
 	}
-	return nil
+
+	return fmt.Sprint(key.Interface())
 }
 
-func copyStructToStruct(source reflect.Value, destination reflect.Value, tag string, flag DecoderFlag) error {
-	sourceType := source.Type()
-	dstType := destination.Type()
+func boolToInt(b bool) int64 {
+	if b {
+		return 1
+	}
 
-	dstTags := make(map[string]int)
+	return 0
+}
 
-	for i := 0; i < destination.NumField(); i++ {
-		if tag != "" {
-			fieldTag := dstType.Field(i).Tag.Get(tag)
-			if fieldTag == "" {
-				continue
-			}
+func (d *decoder) copyStructToMap(source reflect.Value, destination reflect.Value, path string) error {
+	if destination.IsNil() {
+		destination.Set(reflect.MakeMap(destination.Type()))
+	}
 
-			dstTags[fieldTag] = i
-		} else {
-			dstTags[dstType.Field(i).Name] = i
+	for _, f := range fieldsOf(source.Type(), d.tags).fields {
+		srcField, ok := fieldByIndex(source, f.index, false)
+		if !ok || (f.omitEmpty && isEmptyValue(srcField)) {
+			continue
+		}
+		if err := d.setMapField(destination, f.name, srcField, joinPath(path, f.name)); err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	for i := 0; i < source.NumField(); i++ {
-		srcField := source.Field(i)
+// setMapField writes a struct field into destination under key. Nested structs and
+// maps are flattened into separate keys when a key separator is set.
+func (d *decoder) setMapField(destination reflect.Value, key string, srcField reflect.Value, path string) error {
+	if d.flatten(srcField, destination.Type().Elem()) {
+		return d.copyFlattened(srcField, destination, key, path)
+	}
 
-		var sourceFieldName string
+	var data reflect.Value
+	if destination.Type().Elem().Kind() == reflect.Interface {
+		data = d.interfaceValue(srcField, destination.Type().Elem())
+	} else {
+		data = reflect.New(destination.Type().Elem()).Elem()
+	}
 
-		if tag != "" {
-			sourceFieldName = sourceType.Field(i).Tag.Get(tag)
-			if sourceFieldName == "" {
-				continue
-			}
-		} else {
-			sourceFieldName = sourceType.Field(i).Name
-		}
+	err := d.copyValues(srcField, data, path)
+	if err != nil {
+		return err
+	}
 
-		if _, ok := dstTags[sourceFieldName]; !ok {
-			if flag&DecoderStrongFoundDst != 0 {
-				return ErrorDstNotFound
-			}
+	destination.SetMapIndex(reflect.ValueOf(key).Convert(destination.Type().Key()), data)
+
+	return nil
+}
+
+func (d *decoder) copyStructToStruct(source reflect.Value, destination reflect.Value, path string) error {
+	dstFields := fieldsOf(destination.Type(), d.tags)
+	found := make(map[string]bool)
+
+	for _, f := range fieldsOf(source.Type(), d.tags).fields {
+		srcField, ok := fieldByIndex(source, f.index, false)
+		if !ok {
 			continue
 		}
 
-		dstField := destination.Field(dstTags[sourceFieldName])
-		if !dstField.IsValid() || !dstField.CanSet() {
-			return ErrorDstNotSet
+		if err := d.copyNamedField(srcField, destination, f.name, path, dstFields, found, false); err != nil {
+			return err
 		}
+	}
 
-		if dstField.Kind() == reflect.Interface {
-			if flag&DecoderUnwrapStructToMap != 0 && srcField.Kind() == reflect.Struct {
-				dstField = reflect.MakeMap(reflect.TypeOf(map[string]interface{}{}))
-			} else if srcField.Kind() == reflect.Interface {
-				if reflect.ValueOf(srcField.Interface()).Kind() == reflect.Ptr {
-					dstField = reflect.New(reflect.TypeOf(srcField.Interface()).Elem())
-				} else {
-					dstField = reflect.New(reflect.TypeOf(srcField.Interface())).Elem()
-				}
-			} else if srcField.Kind() == reflect.Ptr {
-				dstField = reflect.New(srcField.Elem().Type())
-			} else {
-				dstField = reflect.New(srcField.Type()).Elem()
-			}
-		}
+	if err := d.validateFields(destination, path, found); err != nil {
+		return err
+	}
+
+	d.markUnset(path, dstFields, found)
+
+	return nil
+}
 
-		err := copyValues(srcField, dstField, tag, flag)
-		if err != nil {
+func (d *decoder) copyMapToStruct(source reflect.Value, destination reflect.Value, path string) error {
+	dstFields := fieldsOf(destination.Type(), d.tags)
+	found := make(map[string]bool)
+
+	source, grouped, err := d.expandKeys(source, dstFields, path)
+	if err != nil {
+		return d.fail(err)
+	}
+
+	for _, key := range source.MapKeys() {
+		if err := d.copyNamedField(source.MapIndex(key), destination, key.String(), path, dstFields, found,
+			grouped[key.String()]); err != nil {
 			return err
 		}
+	}
 
-		if dstField.Kind() == destination.Field(dstTags[sourceFieldName]).Kind() {
-			destination.Field(dstTags[sourceFieldName]).Set(dstField)
-		} else if flag&DecoderStrongType == 0 {
-			if converted, err := convertBasicTypes(dstField, destination.Field(dstTags[sourceFieldName]).Type()); err == nil {
-				destination.Field(dstTags[sourceFieldName]).Set(converted)
-				return nil
-			} else {
-				return ErrorTypeMismatch
-			}
-		} else {
-			return ErrorTypeMismatch
+	if d.flag&DecoderMerge == 0 {
+		if err := d.setDefaults(destination, path, found); err != nil {
+			return err
 		}
 	}
+
+	if err := d.validateFields(destination, path, found); err != nil {
+		return err
+	}
+
+	d.markUnset(path, dstFields, found)
+
 	return nil
 }
 
-func copyMapToStruct(source reflect.Value, destination reflect.Value, tag string, flag DecoderFlag) error {
-	dstType := destination.Type()
-	dstTags := make(map[string]int)
+// copyNamedField copies a source value into the destination struct field matched by name.
+// A grouped value of expanded keys is marked as used only when some of its keys were.
+func (d *decoder) copyNamedField(srcField reflect.Value, destination reflect.Value, name string, path string,
+	dstFields *fieldPlan, found map[string]bool, grouped bool) error {
+	fieldPath := joinPath(path, name)
 
-	for i := 0; i < destination.NumField(); i++ {
-		if tag != "" {
-			fieldTag := dstType.Field(i).Tag.Get(tag)
-			if fieldTag == "" {
-				continue
-			}
+	if d.skipZero(srcField) {
+		return nil
+	}
 
-			dstTags[fieldTag] = i
-		} else {
-			dstTags[dstType.Field(i).Name] = i
+	f, ok := d.field(dstFields, name)
+	if !ok {
+		d.markUnused(fieldPath)
+
+		if d.flag&DecoderStrongFoundDst != 0 {
+			return d.fail(newDecodeError(fieldPath, srcField, nil, ErrorDstNotFound, nil))
 		}
+		return nil
 	}
-	for _, key := range source.MapKeys() {
-		sourceFieldName := key.String()
 
-		if _, ok := dstTags[sourceFieldName]; !ok {
-			if flag&DecoderStrongFoundDst != 0 {
-				return ErrorDstNotFound
-			}
-			continue
-		}
+	found[f.name] = true
 
-		srcField := source.MapIndex(key)
+	dstField, ok := fieldByIndex(destination, f.index, true)
+	if !ok {
+		return d.fail(newDecodeError(fieldPath, srcField, nil, ErrorDstNotSet, nil))
+	}
 
-		dstField := destination.Field(dstTags[sourceFieldName])
-		if !dstField.IsValid() || !dstField.CanSet() {
-			return ErrorDstNotSet
-		}
+	parent := d.dstPath
+	if d.meta != nil {
+		d.dstPath = joinPath(parent, f.field)
+	}
 
-		if dstField.Kind() == reflect.Interface {
-			if flag&DecoderUnwrapStructToMap != 0 && srcField.Kind() == reflect.Struct {
-				dstField = reflect.MakeMap(reflect.TypeOf(map[string]interface{}{}))
-			} else if srcField.Kind() == reflect.Interface {
-				if reflect.ValueOf(srcField.Interface()).Kind() == reflect.Ptr {
-					dstField = reflect.New(reflect.TypeOf(srcField.Interface()).Elem())
-				} else {
-					dstField = reflect.New(reflect.TypeOf(srcField.Interface())).Elem()
-				}
-			} else if srcField.Kind() == reflect.Ptr {
-				dstField = reflect.New(srcField.Elem().Type())
-			} else {
-				dstField = reflect.New(srcField.Type()).Elem()
-			}
+	errCount, keyCount := len(d.errs), d.keyCount()
+	err := d.copyField(srcField, dstField, fieldPath)
+
+	if err == nil && errCount == len(d.errs) && (!grouped || d.keyCount() > keyCount) {
+		d.markKey(fieldPath)
+	}
+
+	d.dstPath = parent
+
+	return err
+}
+
+// interfaceValue creates a value holding a copy of source for a destination of interface type.
+func (d *decoder) interfaceValue(source reflect.Value, dstType reflect.Type) reflect.Value {
+	if d.flag&DecoderUnwrapStructToMap != 0 {
+		if _, ok := textMarshaler(source); ok {
+			return reflect.New(reflect.TypeOf("")).Elem()
 		}
+	}
 
-		err := copyValues(srcField, dstField, tag, flag)
-		if err != nil {
-			return err
+	if d.flag&DecoderUnwrapStructToMap != 0 && source.Kind() == reflect.Struct {
+		return reflect.MakeMap(reflect.TypeOf(map[string]interface{}{}))
+	} else if source.Kind() == reflect.Interface {
+		if source.IsNil() {
+			return reflect.New(dstType).Elem()
+		} else if reflect.ValueOf(source.Interface()).Kind() == reflect.Ptr {
+			return reflect.New(reflect.TypeOf(source.Interface()).Elem())
+		} else {
+			return reflect.New(reflect.TypeOf(source.Interface())).Elem()
 		}
+	} else if source.Kind() == reflect.Ptr && !source.IsNil() {
+		return reflect.New(source.Type().Elem())
+	}
 
-		if dstField.Kind() == destination.Field(dstTags[sourceFieldName]).Kind() {
-			destination.Field(dstTags[sourceFieldName]).Set(dstField)
-		} else if flag&DecoderStrongType == 0 {
-			if converted, err := convertBasicTypes(dstField, destination.Field(dstTags[sourceFieldName]).Type()); err == nil {
-				destination.Field(dstTags[sourceFieldName]).Set(converted)
-				return nil
-			} else {
-				return ErrorTypeMismatch
-			}
+	return reflect.New(source.Type()).Elem()
+}
+
+// copyField copies a single struct field or map value into the destination struct field.
+func (d *decoder) copyField(srcField reflect.Value, dstField reflect.Value, path string) error {
+	target := dstField
+	if !target.IsValid() || !target.CanSet() {
+		return d.fail(newDecodeError(path, srcField, nil, ErrorDstNotSet, nil))
+	}
+
+	if merged, ok := d.mergeTarget(dstField, srcField); ok && dstField.Kind() == reflect.Interface {
+		dstField = merged
+	} else if dstField.Kind() == reflect.Interface {
+		dstField = d.interfaceValue(srcField, dstField.Type())
+	}
+
+	err := d.copyValues(srcField, dstField, path)
+	if err != nil {
+		return err
+	}
+
+	if dstField.Kind() == target.Kind() {
+		target.Set(dstField)
+	} else if d.flag&DecoderStrongType == 0 {
+		if converted, err := d.convert(dstField, target.Type()); err == nil {
+			target.Set(converted)
 		} else {
-			return ErrorTypeMismatch
+			return d.fail(newDecodeError(path, dstField, target.Type(), ErrorTypeMismatch, err))
 		}
+	} else {
+		return d.fail(newDecodeError(path, dstField, target.Type(), ErrorTypeMismatch, nil))
 	}
+
 	return nil
 }
 
-func copyMapToMap(source reflect.Value, destination reflect.Value, flag DecoderFlag) error {
+func (d *decoder) copyMapToMap(source reflect.Value, destination reflect.Value, path string) error {
 	if destination.IsNil() {
 		destination.Set(reflect.MakeMap(destination.Type()))
 	}
 
 	for _, key := range source.MapKeys() {
 		sourceValue := source.MapIndex(key)
-		if sourceValue.Kind() == destination.Type().Elem().Kind() {
+		if d.skipZero(sourceValue) {
+			continue
+		}
+
+		if merged, ok := d.mergeTarget(destination.MapIndex(key), sourceValue); ok {
+			if err := d.copyValues(sourceValue, merged, joinPath(path, keyString(key))); err != nil {
+				return err
+			}
+
+			destination.SetMapIndex(key, merged)
+		} else if sourceValue.Kind() == destination.Type().Elem().Kind() {
+			if err := d.checkNumeric(sourceValue, destination.Type().Elem()); err != nil {
+				if err := d.fail(newDecodeError(joinPath(path, keyString(key)), sourceValue, destination.Type().Elem(), ErrorTypeMismatch, err)); err != nil {
+					return err
+				}
+				continue
+			}
+
 			destination.SetMapIndex(key, sourceValue.Convert(destination.Type().Elem()))
-		} else if flag&DecoderStrongType == 0 {
-			if converted, err := convertBasicTypes(sourceValue, destination.Type().Elem()); err == nil {
+		} else if d.flag&DecoderStrongType == 0 {
+			if converted, err := d.convert(sourceValue, destination.Type().Elem()); err == nil {
 				destination.SetMapIndex(key, converted)
-				return nil
-			} else {
-				return ErrorTypeMismatch
+			} else if err := d.fail(newDecodeError(joinPath(path, keyString(key)), sourceValue, destination.Type().Elem(), ErrorTypeMismatch, err)); err != nil {
+				return err
 			}
-		} else {
-			return ErrorTypeMismatch
+		} else if err := d.fail(newDecodeError(joinPath(path, keyString(key)), sourceValue, destination.Type().Elem(), ErrorTypeMismatch, nil)); err != nil {
+			return err
 		}
 	}
 	return nil
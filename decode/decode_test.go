@@ -365,6 +365,53 @@ func TestDecodeMapMap(t *testing.T) {
 	})
 }
 
+func TestDecodeConvertTypes(t *testing.T) {
+	type testStruct struct {
+		Count   int64         `copy:"count"`
+		Ratio   float64       `copy:"ratio"`
+		Enabled bool          `copy:"enabled"`
+		Timeout time.Duration `copy:"timeout"`
+	}
+
+	tests := []struct {
+		name string
+		in   map[string]interface{}
+		want testStruct
+	}{
+		{"test bool to int", map[string]interface{}{"count": true}, testStruct{Count: 1}},
+		{"test bool to float", map[string]interface{}{"ratio": true}, testStruct{Ratio: 1}},
+		{"test int to bool", map[string]interface{}{"enabled": 2}, testStruct{Enabled: true}},
+		{"test zero float to bool", map[string]interface{}{"enabled": 0.0}, testStruct{}},
+		{"test duration string", map[string]interface{}{"timeout": "5s"}, testStruct{Timeout: 5 * time.Second}},
+		{"test integer string as nanoseconds", map[string]interface{}{"timeout": "1"}, testStruct{Timeout: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testOut := testStruct{}
+
+			if err := Decode(tt.in, &testOut, "copy", 0); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			if testOut != tt.want {
+				t.Errorf("Decode() = %+v, want %+v", testOut, tt.want)
+			}
+		})
+	}
+
+	t.Run("test invalid duration", func(t *testing.T) {
+		testOut := testStruct{}
+
+		err := Decode(map[string]interface{}{"timeout": "soon"}, &testOut, "copy", 0)
+
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Path != "timeout" || !errors.Is(err, ErrorTypeMismatch) {
+			t.Errorf("Decode() error = %v, want type mismatch at timeout", err)
+		}
+	})
+}
+
 func TestDecodeMapMapTypes(t *testing.T) {
 	testIn := map[string]interface{}{
 		"1": "1",
@@ -384,7 +431,7 @@ func TestDecodeMapMapTypes(t *testing.T) {
 			t.Errorf("Decode() error = %v", err)
 		}
 
-		if reflect.DeepEqual(testOut, want) {
+		if !reflect.DeepEqual(testOut, want) {
 			t.Errorf("Decode() = %v, want %v", testOut, want)
 		}
 	})
@@ -401,7 +448,7 @@ func TestDecodeMapMapTypes(t *testing.T) {
 			t.Errorf("Decode() error = %v", err)
 		}
 
-		if reflect.DeepEqual(testOut, want) {
+		if !reflect.DeepEqual(testOut, want) {
 			t.Errorf("Decode() = %v, want %v", testOut, want)
 		}
 	})
@@ -423,8 +470,186 @@ func TestDecodeMapMapTypes(t *testing.T) {
 			t.Errorf("Decode() error = %v", err)
 		}
 
-		if reflect.DeepEqual(testOut, want) {
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("Decode() = %v, want %v", testOut, want)
+		}
+	})
+}
+
+func TestDecodeUnsigned(t *testing.T) {
+	type testStruct struct {
+		Port uint16  `copy:"port"`
+		ID   uint64  `copy:"id"`
+		Flag uint8   `copy:"flag"`
+		Ptr  uintptr `copy:"ptr"`
+		Size int     `copy:"size"`
+		Name string  `copy:"name"`
+		On   bool    `copy:"on"`
+	}
+
+	t.Run("test convert to unsigned", func(t *testing.T) {
+		testIn := map[string]interface{}{
+			"port": "8080",
+			"id":   uint64(18446744073709551615),
+			"flag": 255.0,
+			"ptr":  int64(42),
+			"size": uint32(7),
+			"name": uint8(9),
+			"on":   uint(1),
+		}
+		testOut := testStruct{}
+
+		if err := Decode(testIn, &testOut, "copy", 0); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want := testStruct{Port: 8080, ID: 18446744073709551615, Flag: 255, Ptr: 42, Size: 7, Name: "9", On: true}
+		if testOut != want {
+			t.Errorf("Decode() = %+v, want %+v", testOut, want)
+		}
+	})
+
+	tests := []struct {
+		name string
+		in   interface{}
+	}{
+		{name: "negative int", in: -1},
+		{name: "int overflow", in: 300},
+		{name: "uint overflow", in: uint16(256)},
+		{name: "negative float", in: -1.5},
+		{name: "float overflow", in: 1e3},
+		{name: "string overflow", in: "256"},
+		{name: "negative string", in: "-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run("test "+tt.name, func(t *testing.T) {
+			testOut := testStruct{}
+
+			err := Decode(map[string]interface{}{"flag": tt.in}, &testOut, "copy", 0)
+			if !errors.Is(err, ErrorTypeMismatch) {
+				t.Errorf("Decode() error = %v, want %v", err, ErrorTypeMismatch)
+			}
+
+			if testOut.Flag != 0 {
+				t.Errorf("Decode() = %v, want 0", testOut.Flag)
+			}
+		})
+	}
+
+	t.Run("test overflow sentinel", func(t *testing.T) {
+		testOut := testStruct{}
+
+		if err := Decode(map[string]interface{}{"port": 70000}, &testOut, "copy", 0); !errors.Is(err, ErrorOverflow) {
+			t.Errorf("Decode() error = %v, want %v", err, ErrorOverflow)
+		}
+	})
+}
+
+func TestDecodeComplex(t *testing.T) {
+	testIn := map[string]interface{}{
+		"1": "1+2i",
+		"2": 3,
+		"3": uint8(4),
+		"4": 5.5,
+		"5": complex64(6 + 7i),
+	}
+
+	t.Run("test map to map convert to complex", func(t *testing.T) {
+		testOut := map[string]complex128{}
+		want := map[string]complex128{
+			"1": 1 + 2i,
+			"2": 3,
+			"3": 4,
+			"4": 5.5,
+			"5": 6 + 7i,
+		}
+
+		if err := Decode(testIn, &testOut, "copy", 0); err != nil {
+			t.Errorf("Decode() error = %v", err)
+		}
+
+		if !reflect.DeepEqual(testOut, want) {
 			t.Errorf("Decode() = %v, want %v", testOut, want)
 		}
 	})
+
+	t.Run("test complex to string", func(t *testing.T) {
+		testOut := map[string]string{}
+
+		if err := Decode(map[string]interface{}{"c": 1 + 2i}, &testOut, "copy", 0); err != nil || testOut["c"] != "(1+2i)" {
+			t.Errorf("Decode() = %v, error = %v", testOut, err)
+		}
+	})
+}
+
+func TestDecoder(t *testing.T) {
+	type testStruct struct {
+		Name    string    `json:"name"`
+		Port    int16     `json:"port"`
+		Created time.Time `json:"created"`
+	}
+
+	t.Run("test reusable decoder", func(t *testing.T) {
+		d := NewDecoder(WithTag("json"), WithTimeLayouts(time.DateOnly))
+
+		for i, name := range []string{"a", "b"} {
+			testOut := testStruct{}
+
+			if err := d.Decode(map[string]interface{}{"name": name, "port": i, "created": "2024-05-01"}, &testOut); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			want := testStruct{Name: name, Port: int16(i), Created: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)}
+			if testOut != want {
+				t.Errorf("Decode() = %+v, want %+v", testOut, want)
+			}
+		}
+	})
+
+	t.Run("test strict decoder", func(t *testing.T) {
+		d := NewDecoder(WithTag("json"), WithStrict())
+		testOut := testStruct{}
+
+		if err := d.Decode(map[string]interface{}{"title": "a"}, &testOut); !errors.Is(err, ErrorDstNotFound) {
+			t.Errorf("Decode() error = %v, want %v", err, ErrorDstNotFound)
+		}
+
+		if err := d.Decode(map[string]interface{}{"port": 70000}, &testOut); !errors.Is(err, ErrorOverflow) {
+			t.Errorf("Decode() error = %v, want %v", err, ErrorOverflow)
+		}
+	})
+
+	t.Run("test per call options", func(t *testing.T) {
+		d := NewDecoder(WithTag("json"))
+		testOut := testStruct{}
+		metadata := Metadata{}
+
+		if err := d.Decode(map[string]interface{}{"name": "a"}, &testOut, WithMetadata(&metadata)); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if !reflect.DeepEqual(metadata.Keys, []string{"name"}) {
+			t.Errorf("Decode() metadata = %+v", metadata)
+		}
+
+		if err := d.Decode(map[string]interface{}{"title": "a"}, &testOut); err != nil {
+			t.Errorf("Decode() error = %v, per call options leaked", err)
+		}
+	})
+
+	t.Run("test unwrap structs", func(t *testing.T) {
+		testIn := struct {
+			Nested testStruct `json:"nested"`
+		}{Nested: testStruct{Name: "a"}}
+		testOut := map[string]interface{}{}
+
+		if err := NewDecoder(WithTag("json"), WithUnwrapStructs()).Decode(testIn, &testOut); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if _, ok := testOut["nested"].(map[string]interface{}); !ok {
+			t.Errorf("Decode() = %v", testOut)
+		}
+	})
 }
@@ -0,0 +1,109 @@
+package decode
+
+import (
+	"reflect"
+	"strings"
+)
+
+const defaultTag = "default"
+
+// setDefaults fills destination fields missing in found from their default tag.
+// Nested structs without a default tag get their own fields defaulted, nil pointers
+// to structs are allocated when those have defaults.
+func (d *decoder) setDefaults(destination reflect.Value, path string, found map[string]bool) error {
+	return d.setNestedDefaults(destination, path, found, nil)
+}
+
+// setNestedDefaults is setDefaults for a struct nested in the struct types of parents.
+func (d *decoder) setNestedDefaults(destination reflect.Value, path string, found map[string]bool,
+	parents []reflect.Type) error {
+	parents = append(parents, destination.Type())
+
+	for _, f := range fieldsOf(destination.Type(), d.tags).fields {
+		if found[f.name] {
+			continue
+		}
+
+		dstField, ok := fieldByIndex(destination, f.index, false)
+		if !ok || !dstField.CanSet() {
+			continue
+		}
+
+		if err := d.setDefault(dstField, f, joinPath(path, f.name), parents); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *decoder) setDefault(field reflect.Value, f structField, path string, parents []reflect.Type) error {
+	if !field.IsZero() {
+		return nil
+	}
+
+	value := f.defaultValue
+	if !f.hasDefault {
+		switch {
+		case field.Kind() == reflect.Struct:
+			return d.setNestedDefaults(field, path, nil, parents)
+
+		case field.Kind() == reflect.Ptr && hasDefaults(field.Type().Elem(), d.tags, parents):
+			field.Set(reflect.New(field.Type().Elem()))
+			return d.setNestedDefaults(field.Elem(), path, nil, parents)
+		}
+		return nil
+	}
+
+	var source interface{} = value
+
+	target := field.Type()
+	if target.Kind() == reflect.Ptr {
+		target = target.Elem()
+	}
+
+	if target.Kind() == reflect.Slice {
+		if value == "" {
+			return nil
+		}
+		source = strings.Split(value, ",")
+	}
+
+	if field.Kind() == reflect.Ptr {
+		field.Set(reflect.New(target))
+	}
+
+	return d.copyValues(reflect.ValueOf(source), field, path)
+}
+
+// hasDefaults reports whether the struct type t has fields with a default tag, directly
+// or in nested structs. Types in parents are skipped, so recursive pointers stay nil.
+func hasDefaults(t reflect.Type, tags fieldTags, parents []reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t == timeType {
+		return false
+	}
+
+	for _, parent := range parents {
+		if parent == t {
+			return false
+		}
+	}
+	parents = append(parents[:len(parents):len(parents)], t)
+
+	for _, f := range fieldsOf(t, tags).fields {
+		if f.hasDefault {
+			return true
+		}
+
+		ft := t.FieldByIndex(f.index).Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		if hasDefaults(ft, tags, parents) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,118 @@
+package decode
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDecodeDefaults(t *testing.T) {
+	type pool struct {
+		Max     int           `copy:"max" default:"10"`
+		Timeout time.Duration `copy:"timeout" default:"5s"`
+	}
+
+	type testStruct struct {
+		Host    string   `copy:"host" default:"localhost"`
+		Port    int      `copy:"port" default:"8080"`
+		Debug   bool     `copy:"debug" default:"true"`
+		Rate    *float64 `copy:"rate" default:"0.5"`
+		Tags    []string `copy:"tags" default:"a,b,c"`
+		Ports   []int    `copy:"ports" default:"80,443"`
+		Pool    pool     `copy:"pool"`
+		NoValue string   `copy:"no_value"`
+	}
+
+	t.Run("test defaults for missing keys", func(t *testing.T) {
+		testIn := map[string]interface{}{
+			"port": 9090,
+			"pool": map[string]interface{}{"max": 20},
+		}
+		testOut := testStruct{}
+
+		if err := Decode(testIn, &testOut, "copy", 0); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		rate := 0.5
+		want := testStruct{
+			Host:  "localhost",
+			Port:  9090,
+			Debug: true,
+			Rate:  &rate,
+			Tags:  []string{"a", "b", "c"},
+			Ports: []int{80, 443},
+			Pool:  pool{Max: 20, Timeout: 5 * time.Second},
+		}
+
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("Decode() = %+v, want %+v", testOut, want)
+		}
+	})
+
+	t.Run("test defaults for missing nested struct", func(t *testing.T) {
+		testOut := testStruct{}
+
+		if err := Decode(map[string]interface{}{}, &testOut, "copy", 0); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if testOut.Pool.Max != 10 || testOut.Pool.Timeout != 5*time.Second {
+			t.Errorf("Decode() = %+v", testOut.Pool)
+		}
+	})
+
+	t.Run("test defaults for missing pointer to struct", func(t *testing.T) {
+		type node struct {
+			Name string `copy:"name" default:"node"`
+			Next *node  `copy:"next"`
+		}
+
+		testOut := struct {
+			Pool  *pool      `copy:"pool"`
+			Node  node       `copy:"node"`
+			Since *time.Time `copy:"since"`
+			Plain *struct {
+				Name string `copy:"name"`
+			} `copy:"plain"`
+		}{}
+
+		if err := Decode(map[string]interface{}{}, &testOut, "copy", 0); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if testOut.Pool == nil || *testOut.Pool != (pool{Max: 10, Timeout: 5 * time.Second}) {
+			t.Errorf("Decode() Pool = %+v, want defaults", testOut.Pool)
+		}
+
+		if testOut.Node.Name != "node" || testOut.Node.Next != nil || testOut.Since != nil || testOut.Plain != nil {
+			t.Errorf("Decode() = %+v, want nil pointers without defaults", testOut)
+		}
+	})
+
+	t.Run("test defaults keep existing values", func(t *testing.T) {
+		testOut := testStruct{Host: "example.com"}
+
+		if err := Decode(map[string]interface{}{}, &testOut, "copy", 0); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if testOut.Host != "example.com" {
+			t.Errorf("Decode() = %+v", testOut)
+		}
+	})
+
+	t.Run("test invalid default", func(t *testing.T) {
+		testOut := struct {
+			Port int `copy:"port" default:"http"`
+		}{}
+
+		err := Decode(map[string]interface{}{}, &testOut, "copy", 0)
+
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Path != "port" {
+			t.Errorf("Decode() error = %v", err)
+		}
+	})
+}
@@ -0,0 +1,68 @@
+package decode
+
+import (
+	"os"
+	"strings"
+)
+
+const envTag = "env"
+
+// WithEnv sets the environment read by FromEnv instead of os.Environ.
+func WithEnv(env map[string]string) Option {
+	return func(d *Decoder) {
+		d.env = env
+	}
+}
+
+// FromEnv decodes environment variables starting with prefix into destination.
+// Fields are named by the env tag or by the field name in UPPER_CASE, nested structs
+// add their name to the prefix: APP_DB_HOST fills DB.Host for the prefix "APP".
+// Comma-separated values fill slices and the default tag fills absent variables.
+//
+//	var cfg Config
+//	err := decode.FromEnv("APP", &cfg)
+func FromEnv(prefix string, destination interface{}, opts ...Option) error {
+	d := envDecoder(opts...)
+
+	return d.Decode(envValues(prefix, d.env), destination)
+}
+
+func envDecoder(opts ...Option) *Decoder {
+	return NewDecoder(append([]Option{
+		WithTags(envTag),
+		WithFlags(DecoderWeaklyTyped | DecoderCaseInsensitive | DecoderMatchConventions),
+		WithKeySeparator("_"),
+	}, opts...)...)
+}
+
+// envValues returns the variables of env, or of the process when env is nil,
+// starting with prefix, keyed by the rest of their names.
+func envValues(prefix string, env map[string]string) map[string]string {
+	if env == nil {
+		env = environ()
+	}
+
+	if prefix = strings.TrimSuffix(prefix, "_"); prefix != "" {
+		prefix += "_"
+	}
+
+	values := make(map[string]string, len(env))
+	for key, value := range env {
+		if name, ok := strings.CutPrefix(key, prefix); ok && name != "" {
+			values[name] = value
+		}
+	}
+
+	return values
+}
+
+func environ() map[string]string {
+	env := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			env[key] = value
+		}
+	}
+
+	return env
+}
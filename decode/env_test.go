@@ -0,0 +1,107 @@
+package decode
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFromEnv(t *testing.T) {
+	type database struct {
+		Host     string `env:"HOST" default:"localhost"`
+		Port     int    `env:"PORT" default:"5432"`
+		MaxConns int
+	}
+
+	type config struct {
+		Name     string        `env:"NAME" validate:"required"`
+		Debug    bool          `env:"DEBUG"`
+		Timeout  time.Duration `env:"TIMEOUT" default:"5s"`
+		Hosts    []string      `env:"HOSTS"`
+		LogLevel string
+		DB       database          `env:"DB"`
+		Replica  database          `env:"DB_REPLICA"`
+		Labels   map[string]string `env:"LABELS"`
+	}
+
+	t.Run("test decode env", func(t *testing.T) {
+		env := map[string]string{
+			"APP_NAME":            "api",
+			"APP_DEBUG":           "yes",
+			"APP_HOSTS":           "a,b",
+			"APP_LOG_LEVEL":       "info",
+			"APP_DB_HOST":         "db",
+			"APP_DB_MAX_CONNS":    "10",
+			"APP_DB_REPLICA_PORT": "6432",
+			"APP_LABELS_ZONE":     "eu",
+			"OTHER_NAME":          "ignored",
+		}
+		testOut := config{}
+
+		if err := FromEnv("APP", &testOut, WithEnv(env), WithValidate()); err != nil {
+			t.Fatalf("FromEnv() error = %v", err)
+		}
+
+		want := config{
+			Name:     "api",
+			Debug:    true,
+			Timeout:  5 * time.Second,
+			Hosts:    []string{"a", "b"},
+			LogLevel: "info",
+			DB:       database{Host: "db", Port: 5432, MaxConns: 10},
+			Replica:  database{Host: "localhost", Port: 6432},
+			Labels:   map[string]string{"ZONE": "eu"},
+		}
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("FromEnv() = %+v, want %+v", testOut, want)
+		}
+	})
+
+	t.Run("test defaults without env", func(t *testing.T) {
+		testOut := config{}
+
+		if err := FromEnv("APP_", &testOut, WithEnv(map[string]string{})); err != nil {
+			t.Fatalf("FromEnv() error = %v", err)
+		}
+
+		if testOut.Timeout != 5*time.Second || testOut.DB.Port != 5432 || testOut.Replica.Host != "localhost" {
+			t.Errorf("FromEnv() = %+v, want defaults", testOut)
+		}
+	})
+
+	t.Run("test error", func(t *testing.T) {
+		testOut := config{}
+
+		err := FromEnv("APP", &testOut, WithEnv(map[string]string{"APP_DB_PORT": "abc"}))
+		if !errors.Is(err, ErrorTypeMismatch) {
+			t.Errorf("FromEnv() error = %v, want %v", err, ErrorTypeMismatch)
+		}
+	})
+
+	t.Run("test unknown variable", func(t *testing.T) {
+		testOut := config{}
+		var md Metadata
+
+		if err := FromEnv("APP", &testOut, WithEnv(map[string]string{"APP_NAME": "api", "APP_NAEM": "typo"}), WithMetadata(&md)); err != nil {
+			t.Fatalf("FromEnv() error = %v", err)
+		}
+
+		if !reflect.DeepEqual(md.Unused, []string{"NAEM"}) {
+			t.Errorf("Metadata.Unused = %v, want [NAEM]", md.Unused)
+		}
+	})
+
+	t.Run("test os environ", func(t *testing.T) {
+		t.Setenv("DECODE_TEST_NAME", "env")
+		testOut := config{}
+
+		if err := FromEnv("DECODE_TEST", &testOut); err != nil {
+			t.Fatalf("FromEnv() error = %v", err)
+		}
+
+		if testOut.Name != "env" {
+			t.Errorf("FromEnv() Name = %q, want env", testOut.Name)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package decode
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrorDstNotFound   = errors.New("destination not found")
+	ErrorDstNotSet     = errors.New("destination not set")
+	ErrorTypeMismatch  = errors.New("type mismatch")
+	ErrorValidation    = errors.New("validation failed")
+	ErrorOverflow      = errors.New("numeric overflow")
+	ErrorPrecisionLoss = errors.New("numeric precision loss")
+)
+
+// DecodeError describes a failure to decode a single value. It matches one of the
+// sentinel errors (ErrorTypeMismatch, ErrorDstNotFound, ErrorDstNotSet, ErrorValidation) via errors.Is
+// and exposes the underlying cause (e.g. *strconv.NumError) via errors.As.
+type DecodeError struct {
+	Path    string       // Field path, e.g. "servers[3].tls.cert"
+	SrcKind reflect.Kind // Kind of the source value
+	DstType reflect.Type // Type of the destination value, nil if unknown
+	Kind    error        // Sentinel error describing the failure
+	Err     error        // Underlying cause, may be nil
+}
+
+func (e *DecodeError) Error() string {
+	var b strings.Builder
+
+	b.WriteString("decode")
+	if e.Path != "" {
+		b.WriteString(" ")
+		b.WriteString(strconv.Quote(e.Path))
+	}
+
+	b.WriteString(": ")
+	b.WriteString(e.Kind.Error())
+
+	if e.DstType != nil {
+		b.WriteString(" (")
+		b.WriteString(e.SrcKind.String())
+		b.WriteString(" -> ")
+		b.WriteString(e.DstType.String())
+		b.WriteString(")")
+	}
+
+	if e.Err != nil {
+		b.WriteString(": ")
+		b.WriteString(e.Err.Error())
+	}
+
+	return b.String()
+}
+
+func (e *DecodeError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+
+	return []error{e.Kind, e.Err}
+}
+
+// newDecodeError builds a DecodeError, passing through errors that already carry a path.
+func newDecodeError(path string, source reflect.Value, dstType reflect.Type, kind error, err error) error {
+	var decodeErr *DecodeError
+	if errors.As(err, &decodeErr) {
+		return err
+	}
+
+	if errors.Is(err, kind) {
+		err = nil
+	}
+
+	srcKind := reflect.Invalid
+	if source.IsValid() {
+		srcKind = source.Kind()
+		if srcKind == reflect.Interface && !source.IsNil() {
+			srcKind = source.Elem().Kind()
+		}
+	}
+
+	return &DecodeError{
+		Path:    path,
+		SrcKind: srcKind,
+		DstType: dstType,
+		Kind:    kind,
+		Err:     err,
+	}
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+func indexPath(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
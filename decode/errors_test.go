@@ -0,0 +1,157 @@
+package decode
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestDecodeError(t *testing.T) {
+	type tls struct {
+		Port int `copy:"port"`
+	}
+
+	type server struct {
+		Name string `copy:"name"`
+		TLS  tls    `copy:"tls"`
+	}
+
+	type config struct {
+		Servers []server `copy:"servers"`
+		Timeout int      `copy:"timeout"`
+	}
+
+	t.Run("test nested field path", func(t *testing.T) {
+		testOut := struct {
+			Timeout int `copy:"timeout"`
+			TLS     tls `copy:"tls"`
+		}{}
+
+		err := Decode(map[string]interface{}{"tls": map[string]interface{}{"port": "abc"}}, &testOut, "copy", 0)
+
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) {
+			t.Fatalf("Decode() error = %v, want *DecodeError", err)
+		}
+
+		if decodeErr.Path != "tls.port" || decodeErr.SrcKind != reflect.String || decodeErr.DstType != reflect.TypeOf(0) {
+			t.Errorf("Decode() error = %#v", decodeErr)
+		}
+
+		if !errors.Is(err, ErrorTypeMismatch) {
+			t.Errorf("Decode() error = %v, want %v", err, ErrorTypeMismatch)
+		}
+
+		var numErr *strconv.NumError
+		if !errors.As(err, &numErr) {
+			t.Errorf("Decode() error = %v, want *strconv.NumError", err)
+		}
+	})
+
+	t.Run("test slice index path", func(t *testing.T) {
+		testOut := struct {
+			Ports []int `copy:"ports"`
+		}{}
+
+		err := Decode(map[string]interface{}{"ports": []interface{}{"1", "2", "x"}}, &testOut, "copy", 0)
+
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Path != "ports[2]" {
+			t.Errorf("Decode() error = %v, want path ports[2]", err)
+		}
+	})
+
+	t.Run("test not found path", func(t *testing.T) {
+		testOut := config{}
+
+		err := Decode(map[string]interface{}{"unknown": 1}, &testOut, "copy", DecoderStrongFoundDst)
+
+		var decodeErr *DecodeError
+		if !errors.Is(err, ErrorDstNotFound) || !errors.As(err, &decodeErr) || decodeErr.Path != "unknown" {
+			t.Errorf("Decode() error = %v", err)
+		}
+	})
+
+	t.Run("test error message", func(t *testing.T) {
+		err := &DecodeError{
+			Path:    "servers[3].tls.cert",
+			SrcKind: reflect.Int,
+			DstType: reflect.TypeOf(""),
+			Kind:    ErrorTypeMismatch,
+		}
+
+		want := `decode "servers[3].tls.cert": type mismatch (int -> string)`
+		if err.Error() != want {
+			t.Errorf("Error() = %v, want %v", err.Error(), want)
+		}
+	})
+}
+
+func TestDecodeCollectErrors(t *testing.T) {
+	type nested struct {
+		Port int `copy:"port"`
+	}
+
+	type testStruct struct {
+		Name   string  `copy:"name"`
+		Age    int     `copy:"age"`
+		Rate   float64 `copy:"rate"`
+		Nested nested  `copy:"nested"`
+		Ports  []int   `copy:"ports"`
+	}
+
+	testIn := map[string]interface{}{
+		"name":    "John",
+		"age":     "abc",
+		"rate":    "fast",
+		"nested":  map[string]interface{}{"port": "x"},
+		"ports":   []interface{}{1, "y", 3},
+		"unknown": 1,
+	}
+
+	t.Run("test collect all errors", func(t *testing.T) {
+		testOut := testStruct{}
+
+		err := Decode(testIn, &testOut, "copy", DecoderCollectErrors|DecoderStrongFoundDst)
+		if err == nil {
+			t.Fatal("Decode() error = nil")
+		}
+
+		joined, ok := err.(interface{ Unwrap() []error })
+		if !ok {
+			t.Fatalf("Decode() error = %T, want joined error", err)
+		}
+
+		paths := make(map[string]bool)
+		for _, e := range joined.Unwrap() {
+			var decodeErr *DecodeError
+			if !errors.As(e, &decodeErr) {
+				t.Fatalf("Decode() error = %v, want *DecodeError", e)
+			}
+			paths[decodeErr.Path] = true
+		}
+
+		want := map[string]bool{"age": true, "rate": true, "nested.port": true, "ports[1]": true, "unknown": true}
+		if !reflect.DeepEqual(paths, want) {
+			t.Errorf("Decode() paths = %v, want %v", paths, want)
+		}
+
+		if !errors.Is(err, ErrorTypeMismatch) || !errors.Is(err, ErrorDstNotFound) {
+			t.Errorf("Decode() error = %v", err)
+		}
+
+		if testOut.Name != "John" || testOut.Ports[0] != 1 || testOut.Ports[2] != 3 {
+			t.Errorf("Decode() = %v", testOut)
+		}
+	})
+
+	t.Run("test stop on first error", func(t *testing.T) {
+		testOut := testStruct{}
+
+		err := Decode(testIn, &testOut, "copy", 0)
+		if _, ok := err.(*DecodeError); !ok {
+			t.Errorf("Decode() error = %T, want *DecodeError", err)
+		}
+	})
+}
@@ -0,0 +1,282 @@
+package decode
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// structField describes a struct field addressable by name, including fields
+// promoted from embedded and squashed structs.
+type structField struct {
+	name      string
+	field     string // Go field path, e.g. Base.ID for fields of squashed and embedded structs
+	index     []int
+	tagged    bool
+	omitEmpty bool
+
+	defaultValue string
+	hasDefault   bool
+	rules        []string
+}
+
+// fieldPlan is the cached list of decodable fields of a struct type.
+type fieldPlan struct {
+	fields []structField
+	byName map[string]*structField
+
+	byFold       map[string]*structField // Lowercase names
+	byConvention map[string]*structField // Normalized names of untagged fields
+}
+
+// fieldTags selects the struct tags naming fields.
+type fieldTags struct {
+	chain    string // Tag names separated by commas, the first one present on a field wins
+	fallback bool   // Name fields without any tag of the chain by the field name instead of skipping them
+}
+
+// lookup returns the value of the first tag of the chain present on sf.
+func (t fieldTags) lookup(sf reflect.StructField) (string, bool) {
+	for chain := t.chain; chain != ""; {
+		var tag string
+		tag, chain, _ = strings.Cut(chain, ",")
+
+		if value, ok := sf.Tag.Lookup(tag); ok {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+type planKey struct {
+	t    reflect.Type
+	tags fieldTags
+}
+
+var planCache sync.Map
+
+// fieldsOf returns the field plan of t for tags, building it once per type and tags.
+func fieldsOf(t reflect.Type, tags fieldTags) *fieldPlan {
+	key := planKey{t: t, tags: tags}
+	if plan, ok := planCache.Load(key); ok {
+		return plan.(*fieldPlan)
+	}
+
+	plan, _ := planCache.LoadOrStore(key, newFieldPlan(t, tags))
+
+	return plan.(*fieldPlan)
+}
+
+func newFieldPlan(t reflect.Type, tags fieldTags) *fieldPlan {
+	plan := &fieldPlan{fields: structFields(t, tags)}
+
+	plan.byName = make(map[string]*structField, len(plan.fields))
+	plan.byFold = make(map[string]*structField, len(plan.fields))
+	plan.byConvention = make(map[string]*structField, len(plan.fields))
+
+	for i := range plan.fields {
+		f := &plan.fields[i]
+		plan.byName[f.name] = f
+
+		if _, ok := plan.byFold[strings.ToLower(f.name)]; !ok {
+			plan.byFold[strings.ToLower(f.name)] = f
+		}
+
+		if !f.tagged {
+			if _, ok := plan.byConvention[normalizeName(f.name)]; !ok {
+				plan.byConvention[normalizeName(f.name)] = f
+			}
+		}
+	}
+
+	return plan
+}
+
+type tagOptions []string
+
+// parseTag splits a struct tag value into the name and its comma separated options.
+func parseTag(tag string) (string, tagOptions) {
+	name, opts, found := strings.Cut(tag, ",")
+	if !found {
+		return name, nil
+	}
+
+	return name, strings.Split(opts, ",")
+}
+
+func (o tagOptions) has(option string) bool {
+	for _, opt := range o {
+		if opt == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// structFields returns the fields of t matched by name, following Go promotion rules:
+// a shallower field hides deeper ones, and ambiguous fields on the same depth are dropped.
+func structFields(t reflect.Type, tags fieldTags) []structField {
+	var candidates []structField
+	collectFields(t, tags, nil, "", map[reflect.Type]bool{t: true}, &candidates)
+
+	byName := make(map[string][]structField)
+	for _, f := range candidates {
+		byName[f.name] = append(byName[f.name], f)
+	}
+
+	fields := make([]structField, 0, len(byName))
+	for _, list := range byName {
+		if f, ok := dominantField(list); ok {
+			fields = append(fields, f)
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		return lessIndex(fields[i].index, fields[j].index)
+	})
+
+	return fields
+}
+
+func collectFields(t reflect.Type, tags fieldTags, parent []int, parentPath string, visited map[reflect.Type]bool,
+	fields *[]structField) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+
+		var name string
+		var opts tagOptions
+		var hasTag bool
+		if tags.chain != "" {
+			var value string
+			if value, hasTag = tags.lookup(sf); value == "-" {
+				continue
+			}
+			name, opts = parseTag(value)
+		}
+
+		index := make([]int, len(parent)+1)
+		copy(index, parent)
+		index[len(parent)] = i
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		if ft.Kind() == reflect.Struct && (opts.has("squash") || opts.has("inline") || (sf.Anonymous && name == "")) {
+			if !visited[ft] {
+				visited[ft] = true
+				collectFields(ft, tags, index, joinPath(parentPath, sf.Name), visited, fields)
+				delete(visited, ft)
+			}
+			continue
+		}
+
+		if !sf.IsExported() {
+			continue
+		}
+
+		tagged := name != ""
+		if tags.chain != "" && !hasTag && !tags.fallback {
+			continue
+		} else if !tagged {
+			name = sf.Name
+		}
+
+		f := structField{
+			name:      name,
+			field:     joinPath(parentPath, sf.Name),
+			index:     index,
+			tagged:    tagged,
+			omitEmpty: opts.has("omitempty"),
+		}
+		f.defaultValue, f.hasDefault = sf.Tag.Lookup(defaultTag)
+		if rules, ok := sf.Tag.Lookup(validateTag); ok {
+			f.rules = splitRules(rules)
+		}
+
+		*fields = append(*fields, f)
+	}
+}
+
+// dominantField picks the field hiding the others with the same name.
+func dominantField(fields []structField) (structField, bool) {
+	depth := len(fields[0].index)
+	for _, f := range fields[1:] {
+		if len(f.index) < depth {
+			depth = len(f.index)
+		}
+	}
+
+	var found []structField
+	for _, f := range fields {
+		if len(f.index) == depth {
+			found = append(found, f)
+		}
+	}
+
+	if len(found) == 1 {
+		return found[0], true
+	}
+
+	var tagged []structField
+	for _, f := range found {
+		if f.tagged {
+			tagged = append(tagged, f)
+		}
+	}
+
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+
+	return structField{}, false
+}
+
+func lessIndex(a []int, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+
+	return len(a) < len(b)
+}
+
+// isEmptyValue reports whether v is empty in the sense of the omitempty tag option.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+
+	return false
+}
+
+// fieldByIndex returns the nested field of v by index. Nil embedded pointers are
+// allocated when alloc is set, otherwise the field is reported as missing.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v, true
+}
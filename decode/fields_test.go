@@ -0,0 +1,347 @@
+package decode
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type BaseModel struct {
+	ID      int    `copy:"id"`
+	Created string `copy:"created"`
+}
+
+type Audit struct {
+	Author string `copy:"author"`
+}
+
+func TestDecodeEmbedded(t *testing.T) {
+	type user struct {
+		BaseModel
+		*Audit
+		Name string `copy:"name"`
+	}
+
+	t.Run("test map to embedded struct", func(t *testing.T) {
+		testIn := map[string]interface{}{
+			"id":      1,
+			"created": "today",
+			"author":  "admin",
+			"name":    "John",
+		}
+		testOut := user{}
+
+		if err := Decode(testIn, &testOut, "copy", DecoderStrongFoundDst); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if testOut.ID != 1 || testOut.Created != "today" || testOut.Audit == nil || testOut.Author != "admin" || testOut.Name != "John" {
+			t.Errorf("Decode() = %+v", testOut)
+		}
+	})
+
+	t.Run("test embedded struct to map", func(t *testing.T) {
+		testIn := user{BaseModel: BaseModel{ID: 1, Created: "today"}, Name: "John"}
+		testOut := map[string]interface{}{}
+
+		if err := Decode(testIn, &testOut, "copy", 0); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want := map[string]interface{}{"id": 1, "created": "today", "name": "John"}
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("Decode() = %v, want %v", testOut, want)
+		}
+	})
+
+	t.Run("test embedded struct to struct by name", func(t *testing.T) {
+		testIn := struct {
+			BaseModel
+			Name string
+		}{BaseModel: BaseModel{ID: 7}, Name: "John"}
+		testOut := struct {
+			ID   int
+			Name string
+		}{}
+
+		if err := Decode(testIn, &testOut, "", 0); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if testOut.ID != 7 || testOut.Name != "John" {
+			t.Errorf("Decode() = %+v", testOut)
+		}
+	})
+
+	t.Run("test squash named field", func(t *testing.T) {
+		testIn := map[string]interface{}{"id": 3, "author": "admin"}
+		testOut := struct {
+			Base  BaseModel `copy:",squash"`
+			Audit Audit     `copy:",inline"`
+		}{}
+
+		if err := Decode(testIn, &testOut, "copy", DecoderStrongFoundDst); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if testOut.Base.ID != 3 || testOut.Audit.Author != "admin" {
+			t.Errorf("Decode() = %+v", testOut)
+		}
+	})
+
+	t.Run("test shallow field hides promoted", func(t *testing.T) {
+		testIn := map[string]interface{}{"id": 5}
+		testOut := struct {
+			BaseModel
+			ID string `copy:"id"`
+		}{}
+
+		if err := Decode(testIn, &testOut, "copy", 0); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if testOut.ID != "5" || testOut.BaseModel.ID != 0 {
+			t.Errorf("Decode() = %+v", testOut)
+		}
+	})
+
+	t.Run("test tagged embedded struct is nested", func(t *testing.T) {
+		testIn := map[string]interface{}{"base": map[string]interface{}{"id": 9}}
+		testOut := struct {
+			BaseModel `copy:"base"`
+		}{}
+
+		if err := Decode(testIn, &testOut, "copy", 0); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if testOut.ID != 9 {
+			t.Errorf("Decode() = %+v", testOut)
+		}
+	})
+}
+
+func TestDecodeTagOptions(t *testing.T) {
+	type testStruct struct {
+		Name     string            `json:"name,omitempty"`
+		Age      int               `json:"age,omitempty"`
+		Email    string            `json:",omitempty"`
+		Password string            `json:"-"`
+		Dash     string            `json:"-,"`
+		Tags     []string          `json:"tags,omitempty"`
+		Labels   map[string]string `json:"labels,omitempty"`
+		Parent   *testStruct       `json:"parent,omitempty"`
+		Skipped  string
+	}
+
+	t.Run("test struct to map omitempty", func(t *testing.T) {
+		testIn := testStruct{Name: "John", Password: "secret", Dash: "dash", Skipped: "value"}
+		testOut := map[string]interface{}{}
+
+		if err := Decode(testIn, &testOut, "json", 0); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want := map[string]interface{}{"name": "John", "-": "dash"}
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("Decode() = %v, want %v", testOut, want)
+		}
+	})
+
+	t.Run("test map to struct with options", func(t *testing.T) {
+		testIn := map[string]interface{}{
+			"name":     "John",
+			"age":      30,
+			"Email":    "john@example.com",
+			"-":        "dash",
+			"password": "secret",
+		}
+		testOut := testStruct{}
+
+		if err := Decode(testIn, &testOut, "json", 0); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want := testStruct{Name: "John", Age: 30, Email: "john@example.com", Dash: "dash"}
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("Decode() = %+v, want %+v", testOut, want)
+		}
+	})
+
+	t.Run("test skip field strong found dst", func(t *testing.T) {
+		testOut := testStruct{}
+
+		if err := Decode(map[string]interface{}{"Password": "secret"}, &testOut, "json", DecoderStrongFoundDst); err == nil {
+			t.Errorf("Decode() error = nil, want %v", ErrorDstNotFound)
+		}
+	})
+}
+
+func TestDecodeTagChain(t *testing.T) {
+	type config struct {
+		Host    string `mapstructure:"host_name" json:"host"`
+		Port    int    `json:"port"`
+		Timeout string
+		Secret  string `mapstructure:"-" json:"secret"`
+		Debug   bool   `json:",omitempty"`
+	}
+
+	t.Run("test map to struct", func(t *testing.T) {
+		testIn := map[string]interface{}{
+			"host_name": "localhost",
+			"host":      "ignored",
+			"port":      8080,
+			"Timeout":   "5s",
+			"secret":    "ignored",
+			"Debug":     true,
+		}
+		testOut := config{}
+
+		if err := Decode(testIn, &testOut, "", 0, WithTags("mapstructure", "json")); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want := config{Host: "localhost", Port: 8080, Timeout: "5s", Debug: true}
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("Decode() = %+v, want %+v", testOut, want)
+		}
+	})
+
+	t.Run("test struct to map", func(t *testing.T) {
+		testIn := config{Host: "localhost", Port: 8080, Timeout: "5s"}
+		testOut := map[string]interface{}{}
+
+		if err := NewDecoder(WithTags("json")).Decode(testIn, &testOut); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want := map[string]interface{}{"host": "localhost", "port": 8080, "Timeout": "5s", "secret": ""}
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("Decode() = %v, want %v", testOut, want)
+		}
+	})
+
+	t.Run("test single tag skips untagged fields", func(t *testing.T) {
+		testOut := config{}
+
+		if err := Decode(map[string]interface{}{"Timeout": "5s", "port": 1}, &testOut, "json", 0); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want := config{Port: 1}
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("Decode() = %+v, want %+v", testOut, want)
+		}
+	})
+}
+
+type benchAddress struct {
+	City   string `json:"city"`
+	Street string `json:"street"`
+	Zip    int    `json:"zip"`
+}
+
+type benchMeta struct {
+	ID      int    `json:"id"`
+	Created string `json:"created"`
+}
+
+type benchPayload struct {
+	benchMeta `json:",squash"`
+	Name      string            `json:"name" validate:"required"`
+	Email     string            `json:"email"`
+	Age       int               `json:"age" default:"18"`
+	Score     float64           `json:"score"`
+	Active    bool              `json:"active"`
+	Tags      []string          `json:"tags"`
+	Labels    map[string]string `json:"labels"`
+	Address   benchAddress      `json:"address"`
+}
+
+func benchMap() map[string]interface{} {
+	return map[string]interface{}{
+		"id":     1,
+		"name":   "John",
+		"email":  "john@example.com",
+		"score":  9.5,
+		"active": true,
+		"tags":   []interface{}{"a", "b"},
+		"labels": map[string]string{"env": "prod"},
+		"address": map[string]interface{}{
+			"city":   "Berlin",
+			"street": "Main",
+			"zip":    10115,
+		},
+	}
+}
+
+func BenchmarkDecodeMapToStruct(b *testing.B) {
+	src := benchMap()
+
+	run := func(b *testing.B, uncached bool) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			if uncached {
+				planCache.Clear()
+			}
+
+			out := benchPayload{}
+			if err := Decode(src, &out, "json", DecoderValidate); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	b.Run("cached", func(b *testing.B) { run(b, false) })
+	b.Run("uncached", func(b *testing.B) { run(b, true) })
+}
+
+func BenchmarkDecodeStructToMap(b *testing.B) {
+	src := benchPayload{}
+	if err := Decode(benchMap(), &src, "json", 0); err != nil {
+		b.Fatal(err)
+	}
+
+	run := func(b *testing.B, uncached bool) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			if uncached {
+				planCache.Clear()
+			}
+
+			out := map[string]interface{}{}
+			if err := Decode(src, &out, "json", 0); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+
+	b.Run("cached", func(b *testing.B) { run(b, false) })
+	b.Run("uncached", func(b *testing.B) { run(b, true) })
+}
+
+func TestFieldPlanConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			out := benchPayload{}
+			if err := Decode(benchMap(), &out, "json", 0); err != nil {
+				t.Error(err)
+				return
+			}
+
+			if out.Name != "John" || out.Address.Zip != 10115 || out.ID != 1 {
+				t.Errorf("Decode() = %+v", out)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
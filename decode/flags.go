@@ -0,0 +1,45 @@
+package decode
+
+import (
+	"flag"
+)
+
+const flagTag = "flag"
+
+// FromFlags decodes the values of set, including the defaults of flags absent from
+// the command line, into destination. Fields are named by the flag tag or match the
+// field name in any case or naming convention, nested structs use dotted flag names
+// ("db.host"). Call it after set.Parse.
+func FromFlags(set *flag.FlagSet, destination interface{}, opts ...Option) error {
+	return flagDecoder(opts...).Decode(flagValues(set, true), destination)
+}
+
+func flagDecoder(opts ...Option) *Decoder {
+	return NewDecoder(append([]Option{
+		WithTags(flagTag),
+		WithFlags(DecoderWeaklyTyped | DecoderCaseInsensitive | DecoderMatchConventions),
+		WithKeySeparator("."),
+	}, opts...)...)
+}
+
+// flagValues returns the values of the flags of set by name, typed when the flag
+// implements flag.Getter. Only flags set on the command line are returned unless all is set.
+func flagValues(set *flag.FlagSet, all bool) map[string]interface{} {
+	values := make(map[string]interface{})
+
+	visit := func(f *flag.Flag) {
+		if getter, ok := f.Value.(flag.Getter); ok {
+			values[f.Name] = getter.Get()
+		} else {
+			values[f.Name] = f.Value.String()
+		}
+	}
+
+	if all {
+		set.VisitAll(visit)
+	} else {
+		set.Visit(visit)
+	}
+
+	return values
+}
@@ -0,0 +1,49 @@
+package decode
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFromFlags(t *testing.T) {
+	type config struct {
+		Addr    string        `flag:"addr"`
+		Timeout time.Duration `flag:"timeout"`
+		Verbose bool
+		MaxConn int
+		Peers   []string `flag:"peers"`
+		DB      struct {
+			Host string `flag:"host"`
+		} `flag:"db"`
+	}
+
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	set.String("addr", ":8080", "")
+	set.Duration("timeout", time.Second, "")
+	set.Bool("verbose", false, "")
+	set.Int("max-conn", 10, "")
+	set.String("peers", "", "")
+	set.String("db.host", "localhost", "")
+
+	if err := set.Parse([]string{"-verbose", "-timeout=5s", "-peers=a,b", "-db.host=db"}); err != nil {
+		t.Fatal(err)
+	}
+
+	testOut := config{}
+	if err := FromFlags(set, &testOut); err != nil {
+		t.Fatalf("FromFlags() error = %v", err)
+	}
+
+	want := config{Addr: ":8080", Timeout: 5 * time.Second, Verbose: true, MaxConn: 10, Peers: []string{"a", "b"}}
+	want.DB.Host = "db"
+
+	if !reflect.DeepEqual(testOut, want) {
+		t.Errorf("FromFlags() = %+v, want %+v", testOut, want)
+	}
+
+	if values := flagValues(set, false); len(values) != 4 {
+		t.Errorf("flagValues() = %v, want 4 set flags", values)
+	}
+}
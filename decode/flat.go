@@ -0,0 +1,148 @@
+package decode
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// WithKeySeparator expands flat source keys like "db.pool.max" into nested structs
+// and maps, and flattens nested structs into such keys when decoding into a map.
+func WithKeySeparator(separator string) Option {
+	return func(d *Decoder) {
+		d.separator = separator
+	}
+}
+
+// expandKeys groups the keys of a map with string keys by their leading segment, so
+// {"db.host": h} becomes {"db": {"host": h}}, and reports the grouped heads. Keys
+// matching a destination field as is or whose head matches none are kept, the
+// remaining segments are expanded by the nested decoding.
+func (d *decoder) expandKeys(source reflect.Value, dstFields *fieldPlan, path string) (reflect.Value, map[string]bool, error) {
+	if d.separator == "" || source.Type().Key().Kind() != reflect.String {
+		return source, nil, nil
+	}
+
+	expanded := make(map[string]interface{}, source.Len())
+	groups := make(map[string]map[string]interface{})
+
+	for _, key := range source.MapKeys() {
+		name := key.String()
+
+		head, rest, ok := d.splitKey(name, dstFields)
+		if _, found := d.field(dstFields, name); found || !ok {
+			expanded[name] = source.MapIndex(key).Interface()
+			continue
+		}
+
+		if groups[head] == nil {
+			groups[head] = make(map[string]interface{})
+		}
+		groups[head][rest] = source.MapIndex(key).Interface()
+	}
+
+	if len(groups) == 0 {
+		return source, nil, nil
+	}
+
+	grouped := make(map[string]bool, len(groups))
+
+	for head, group := range groups {
+		if value, ok := expanded[head]; ok {
+			nested := reflect.ValueOf(value)
+			if nested.Kind() != reflect.Map || nested.Type().Key().Kind() != reflect.String {
+				return reflect.Value{}, nil, newDecodeError(joinPath(path, head), nested, nil, ErrorTypeMismatch,
+					errors.New("value conflicts with nested keys"))
+			}
+
+			for _, key := range nested.MapKeys() {
+				if _, ok := group[key.String()]; !ok {
+					group[key.String()] = nested.MapIndex(key).Interface()
+				}
+			}
+		}
+
+		expanded[head] = group
+		grouped[head] = true
+	}
+
+	return reflect.ValueOf(expanded), grouped, nil
+}
+
+// splitKey splits a key at the last separator whose head names a destination field,
+// so "db_replica_port" fills DbReplica.Port rather than Db. It reports false when no
+// head does.
+func (d *decoder) splitKey(name string, dstFields *fieldPlan) (string, string, bool) {
+	for i := strings.LastIndex(name, d.separator); i > 0; i = strings.LastIndex(name[:i], d.separator) {
+		if _, ok := d.field(dstFields, name[:i]); ok {
+			return name[:i], name[i+len(d.separator):], true
+		}
+	}
+
+	return "", "", false
+}
+
+// flatten reports whether a struct field is written as separate keys of the destination map.
+func (d *decoder) flatten(field reflect.Value, dstType reflect.Type) bool {
+	if d.separator == "" {
+		return false
+	}
+
+	for field.Kind() == reflect.Interface || field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return false
+		}
+		field = field.Elem()
+	}
+
+	switch {
+	case field.Kind() == reflect.Map:
+		if field.Type().Key().Kind() != reflect.String {
+			return false
+		}
+
+	case field.Kind() != reflect.Struct || field.Type() == timeType:
+		return false
+	}
+
+	if _, ok := textMarshaler(field); ok {
+		return false
+	}
+
+	_, ok := d.lookupHook(field.Type(), dstType)
+
+	return !ok
+}
+
+// copyFlattened writes the fields of a nested struct or the entries of a nested map
+// into destination under keys prefixed by prefix.
+func (d *decoder) copyFlattened(field reflect.Value, destination reflect.Value, prefix string, path string) error {
+	for field.Kind() == reflect.Interface || field.Kind() == reflect.Ptr {
+		field = field.Elem()
+	}
+
+	if field.Kind() == reflect.Map {
+		iter := field.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			if err := d.setMapField(destination, prefix+d.separator+key, iter.Value(), joinPath(path, key)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for _, f := range fieldsOf(field.Type(), d.tags).fields {
+		srcField, ok := fieldByIndex(field, f.index, false)
+		if !ok || (f.omitEmpty && isEmptyValue(srcField)) {
+			continue
+		}
+
+		if err := d.setMapField(destination, prefix+d.separator+f.name, srcField, joinPath(path, f.name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package decode
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDecodeKeySeparator(t *testing.T) {
+	type pool struct {
+		Max int `json:"max"`
+		Min int `json:"min"`
+	}
+
+	type database struct {
+		Host string `json:"host"`
+		Pool *pool  `json:"pool"`
+	}
+
+	type config struct {
+		Name    string            `json:"name"`
+		DB      database          `json:"db"`
+		Labels  map[string]string `json:"labels"`
+		Version string            `json:"app.version"`
+		Started time.Time         `json:"started"`
+	}
+
+	t.Run("test expand flat map", func(t *testing.T) {
+		testIn := map[string]string{
+			"name":         "api",
+			"db.host":      "localhost",
+			"db.pool.max":  "10",
+			"db.pool.min":  "2",
+			"labels.env":   "prod",
+			"labels.a.b":   "c",
+			"app.version":  "1.2",
+			"unknown.path": "x",
+		}
+		testOut := config{}
+		var md Metadata
+
+		if err := Decode(testIn, &testOut, "json", 0, WithKeySeparator("."), WithMetadata(&md)); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want := config{
+			Name:    "api",
+			DB:      database{Host: "localhost", Pool: &pool{Max: 10, Min: 2}},
+			Labels:  map[string]string{"env": "prod", "a.b": "c"},
+			Version: "1.2",
+		}
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("Decode() = %+v, want %+v", testOut, want)
+		}
+
+		if !reflect.DeepEqual(md.Unused, []string{"unknown.path"}) {
+			t.Errorf("Metadata.Unused = %v, want [unknown.path]", md.Unused)
+		}
+	})
+
+	t.Run("test expand unknown keys", func(t *testing.T) {
+		testIn := map[string]string{"name": "api", "cache.size": "10", "db.hots": "localhost"}
+		testOut := config{}
+		var md Metadata
+
+		if err := Decode(testIn, &testOut, "json", 0, WithKeySeparator("."), WithMetadata(&md)); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if !reflect.DeepEqual(md.Keys, []string{"name"}) {
+			t.Errorf("Metadata.Keys = %v, want [name]", md.Keys)
+		}
+
+		if !reflect.DeepEqual(md.Unused, []string{"cache.size", "db.hots"}) {
+			t.Errorf("Metadata.Unused = %v, want [cache.size db.hots]", md.Unused)
+		}
+
+		err := Decode(map[string]string{"cache.size": "10"}, &testOut, "json", DecoderStrongFoundDst, WithKeySeparator("."))
+
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Path != "cache.size" || !errors.Is(err, ErrorDstNotFound) {
+			t.Errorf("Decode() error = %v, want dst not found at cache.size", err)
+		}
+	})
+
+	t.Run("test expand merges nested map", func(t *testing.T) {
+		testIn := map[string]interface{}{
+			"db":            map[string]interface{}{"host": "localhost"},
+			"db__pool__max": 10,
+		}
+		testOut := config{}
+
+		if err := Decode(testIn, &testOut, "json", 0, WithKeySeparator("__")); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want := database{Host: "localhost", Pool: &pool{Max: 10}}
+		if !reflect.DeepEqual(testOut.DB, want) {
+			t.Errorf("Decode() = %+v, want %+v", testOut.DB, want)
+		}
+	})
+
+	t.Run("test expand conflict", func(t *testing.T) {
+		testOut := config{}
+
+		err := Decode(map[string]interface{}{"db": "x", "db.host": "y"}, &testOut, "json", 0, WithKeySeparator("."))
+
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Path != "db" || !errors.Is(err, ErrorTypeMismatch) {
+			t.Errorf("Decode() error = %v, want type mismatch at db", err)
+		}
+	})
+
+	t.Run("test flatten struct", func(t *testing.T) {
+		started := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		testIn := config{
+			Name:    "api",
+			DB:      database{Host: "localhost", Pool: &pool{Max: 10}},
+			Labels:  map[string]string{"env": "prod"},
+			Version: "1.2",
+			Started: started,
+		}
+		testOut := map[string]interface{}{}
+
+		if err := Decode(testIn, &testOut, "json", 0, WithKeySeparator(".")); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want := map[string]interface{}{
+			"name":        "api",
+			"db.host":     "localhost",
+			"db.pool.max": 10,
+			"db.pool.min": 0,
+			"labels.env":  "prod",
+			"app.version": "1.2",
+			"started":     started,
+		}
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("Decode() = %v, want %v", testOut, want)
+		}
+	})
+
+	t.Run("test round trip", func(t *testing.T) {
+		testIn := config{Name: "api", DB: database{Host: "localhost", Pool: &pool{Max: 10, Min: 1}}}
+		flat := map[string]string{}
+
+		if err := Decode(testIn, &flat, "json", 0, WithKeySeparator("_")); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if flat["db_pool_max"] != "10" {
+			t.Errorf("Decode() = %v, want db_pool_max = 10", flat)
+		}
+
+		testOut := config{}
+		if err := Decode(flat, &testOut, "json", 0, WithKeySeparator("_")); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if !reflect.DeepEqual(testOut.DB, testIn.DB) {
+			t.Errorf("Decode() = %+v, want %+v", testOut.DB, testIn.DB)
+		}
+	})
+}
@@ -0,0 +1,24 @@
+package decode
+
+// DecodeAs decodes source into a new value of type T.
+//
+//	cfg, err := decode.DecodeAs[Config](source, decode.WithTag("json"))
+func DecodeAs[T any](source interface{}, opts ...Option) (T, error) {
+	var res T
+	err := Decode(source, &res, "", 0, opts...)
+
+	return res, err
+}
+
+// DecodeInto decodes source into destination with a compile-time checked destination type.
+func DecodeInto[T any](source interface{}, destination *T, opts ...Option) error {
+	return Decode(source, destination, "", 0, opts...)
+}
+
+// DecodeSlice decodes every element of source into a new slice of T.
+func DecodeSlice[T any](source []interface{}, opts ...Option) ([]T, error) {
+	var res []T
+	err := Decode(source, &res, "", 0, opts...)
+
+	return res, err
+}
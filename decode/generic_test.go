@@ -0,0 +1,59 @@
+package decode
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeGeneric(t *testing.T) {
+	type server struct {
+		Host string `json:"host"`
+		Port int    `json:"port"`
+	}
+
+	t.Run("test decode as", func(t *testing.T) {
+		res, err := DecodeAs[server](map[string]interface{}{"host": "a", "port": "80"}, WithTag("json"))
+		if err != nil || res != (server{Host: "a", Port: 80}) {
+			t.Errorf("DecodeAs() = %+v, error = %v", res, err)
+		}
+	})
+
+	t.Run("test decode as pointer", func(t *testing.T) {
+		res, err := DecodeAs[*server](map[string]interface{}{"host": "a"}, WithTag("json"))
+		if err != nil || res == nil || res.Host != "a" {
+			t.Errorf("DecodeAs() = %+v, error = %v", res, err)
+		}
+	})
+
+	t.Run("test decode as with flags", func(t *testing.T) {
+		_, err := DecodeAs[server](map[string]interface{}{"name": "a"}, WithTag("json"), WithFlags(DecoderStrongFoundDst))
+		if !errors.Is(err, ErrorDstNotFound) {
+			t.Errorf("DecodeAs() error = %v", err)
+		}
+	})
+
+	t.Run("test decode into", func(t *testing.T) {
+		res := server{Port: 443}
+		if err := DecodeInto(map[string]interface{}{"host": "a"}, &res, WithTag("json")); err != nil || res != (server{Host: "a", Port: 443}) {
+			t.Errorf("DecodeInto() = %+v, error = %v", res, err)
+		}
+	})
+
+	t.Run("test decode slice", func(t *testing.T) {
+		src := []interface{}{
+			map[string]interface{}{"host": "a", "port": 80},
+			map[string]interface{}{"host": "b", "port": "443"},
+		}
+
+		res, err := DecodeSlice[server](src, WithTag("json"))
+		if err != nil || !reflect.DeepEqual(res, []server{{Host: "a", Port: 80}, {Host: "b", Port: 443}}) {
+			t.Errorf("DecodeSlice() = %+v, error = %v", res, err)
+		}
+
+		ints, err := DecodeSlice[int]([]interface{}{"1", 2, 3.0})
+		if err != nil || !reflect.DeepEqual(ints, []int{1, 2, 3}) {
+			t.Errorf("DecodeSlice() = %v, error = %v", ints, err)
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package decode
+
+import (
+	"reflect"
+	"sync"
+)
+
+// DecodeHook converts a source value before it is decoded into the destination.
+// The result is set directly when it is assignable to the destination type,
+// otherwise it is decoded further by the built-in conversions.
+type DecodeHook func(data interface{}) (interface{}, error)
+
+type hookKey struct {
+	from reflect.Type
+	to   reflect.Type
+}
+
+// Hooks is a concurrency-safe registry of DecodeHook keyed by source and destination types.
+type Hooks struct {
+	mu    sync.RWMutex
+	hooks map[hookKey]DecodeHook
+}
+
+// DefaultHooks is consulted by every Decode call after the per-call hooks.
+var DefaultHooks = NewHooks()
+
+func NewHooks() *Hooks {
+	return &Hooks{hooks: make(map[hookKey]DecodeHook)}
+}
+
+// Register sets the hook converting from type into to type, replacing a previous one.
+// A nil hook removes the registration.
+func (h *Hooks) Register(from reflect.Type, to reflect.Type, hook DecodeHook) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if hook == nil {
+		delete(h.hooks, hookKey{from: from, to: to})
+		return
+	}
+
+	h.hooks[hookKey{from: from, to: to}] = hook
+}
+
+// Lookup returns the hook converting from type into to type.
+func (h *Hooks) Lookup(from reflect.Type, to reflect.Type) (DecodeHook, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	hook, ok := h.hooks[hookKey{from: from, to: to}]
+
+	return hook, ok
+}
+
+// RegisterHook registers a typed conversion function in hooks.
+func RegisterHook[From, To any](hooks *Hooks, fn func(From) (To, error)) {
+	hooks.Register(typeOf[From](), typeOf[To](), func(data interface{}) (interface{}, error) {
+		return fn(data.(From))
+	})
+}
+
+// ComposeHooks chains hooks, passing the result of each hook to the next one.
+func ComposeHooks(hooks ...DecodeHook) DecodeHook {
+	return func(data interface{}) (interface{}, error) {
+		var err error
+		for _, hook := range hooks {
+			if data, err = hook(data); err != nil {
+				return nil, err
+			}
+		}
+
+		return data, nil
+	}
+}
+
+// WithHooks sets hooks consulted before DefaultHooks.
+func WithHooks(hooks *Hooks) Option {
+	return func(d *Decoder) {
+		d.hooks = hooks
+	}
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (d *decoder) lookupHook(from reflect.Type, to reflect.Type) (DecodeHook, bool) {
+	if d.hooks != nil {
+		if hook, ok := d.hooks.Lookup(from, to); ok {
+			return hook, true
+		}
+	}
+
+	return DefaultHooks.Lookup(from, to)
+}
+
+// applyHook runs the hook registered for the source and destination types. It reports
+// whether the value was handled; otherwise source holds the value to decode further.
+func (d *decoder) applyHook(source *reflect.Value, destination reflect.Value, path string) (bool, error) {
+	src := *source
+	for src.Kind() == reflect.Interface && !src.IsNil() {
+		src = src.Elem()
+	}
+
+	if !src.IsValid() || !src.CanInterface() || (src.Kind() == reflect.Interface && src.IsNil()) {
+		return false, nil
+	}
+
+	hook, ok := d.lookupHook(src.Type(), destination.Type())
+	if !ok {
+		return false, nil
+	}
+
+	res, err := hook(src.Interface())
+	if err != nil {
+		return true, d.fail(newDecodeError(path, src, destination.Type(), ErrorTypeMismatch, err))
+	}
+
+	resVal := reflect.ValueOf(res)
+	if !resVal.IsValid() {
+		destination.Set(reflect.Zero(destination.Type()))
+		return true, nil
+	}
+
+	if resVal.Type().AssignableTo(destination.Type()) {
+		destination.Set(resVal)
+		return true, nil
+	} else if resVal.Type() != src.Type() {
+		return true, d.copyValues(resVal, destination, path)
+	}
+
+	*source = resVal
+
+	return false, nil
+}
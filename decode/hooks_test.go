@@ -0,0 +1,145 @@
+package decode
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeHooks(t *testing.T) {
+	type testStruct struct {
+		IP      net.IP    `copy:"ip"`
+		URL     *url.URL  `copy:"url"`
+		Created time.Time `copy:"created"`
+		Name    string    `copy:"name"`
+	}
+
+	hooks := NewHooks()
+	RegisterHook(hooks, func(s string) (net.IP, error) {
+		if ip := net.ParseIP(s); ip != nil {
+			return ip, nil
+		}
+		return nil, errors.New("invalid ip")
+	})
+	RegisterHook(hooks, url.Parse)
+	RegisterHook(hooks, func(i int64) (time.Time, error) {
+		return time.Unix(i, 0).UTC(), nil
+	})
+
+	t.Run("test hooks", func(t *testing.T) {
+		testIn := map[string]interface{}{
+			"ip":      "127.0.0.1",
+			"url":     "https://example.com/path",
+			"created": int64(1700000000),
+			"name":    "John",
+		}
+		testOut := testStruct{}
+
+		if err := Decode(testIn, &testOut, "copy", 0, WithHooks(hooks)); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if !testOut.IP.Equal(net.ParseIP("127.0.0.1")) || testOut.URL == nil || testOut.URL.Host != "example.com" ||
+			!testOut.Created.Equal(time.Unix(1700000000, 0)) || testOut.Name != "John" {
+			t.Errorf("Decode() = %+v", testOut)
+		}
+	})
+
+	t.Run("test hook error", func(t *testing.T) {
+		testOut := testStruct{}
+
+		err := Decode(map[string]interface{}{"ip": "localhost"}, &testOut, "copy", 0, WithHooks(hooks))
+
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Path != "ip" || !errors.Is(err, ErrorTypeMismatch) {
+			t.Errorf("Decode() error = %v", err)
+		}
+	})
+
+	t.Run("test per call hooks override defaults", func(t *testing.T) {
+		RegisterHook(DefaultHooks, func(s string) (net.IP, error) {
+			return net.IPv4zero, nil
+		})
+		defer DefaultHooks.Register(typeOf[string](), typeOf[net.IP](), nil)
+
+		testOut := testStruct{}
+
+		if err := Decode(map[string]interface{}{"ip": "10.0.0.1"}, &testOut, "copy", 0); err != nil || !testOut.IP.Equal(net.IPv4zero) {
+			t.Errorf("Decode() = %v, error = %v", testOut.IP, err)
+		}
+
+		if err := Decode(map[string]interface{}{"ip": "10.0.0.1"}, &testOut, "copy", 0, WithHooks(hooks)); err != nil ||
+			!testOut.IP.Equal(net.ParseIP("10.0.0.1")) {
+			t.Errorf("Decode() = %v, error = %v", testOut.IP, err)
+		}
+	})
+
+	t.Run("test compose hooks", func(t *testing.T) {
+		composed := NewHooks()
+		composed.Register(typeOf[string](), typeOf[[]string](), ComposeHooks(
+			func(data interface{}) (interface{}, error) {
+				return strings.TrimSpace(data.(string)), nil
+			},
+			func(data interface{}) (interface{}, error) {
+				return strings.Split(data.(string), ";"), nil
+			},
+		))
+
+		testOut := struct {
+			List []string `copy:"list"`
+		}{}
+
+		if err := Decode(map[string]interface{}{"list": " a;b "}, &testOut, "copy", 0, WithHooks(composed)); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if !reflect.DeepEqual(testOut.List, []string{"a", "b"}) {
+			t.Errorf("Decode() = %v", testOut.List)
+		}
+	})
+
+	t.Run("test hooks for pointer fields", func(t *testing.T) {
+		type size struct {
+			Width, Height int
+		}
+
+		sizes := NewHooks()
+		RegisterHook(sizes, func(s string) (size, error) {
+			var res size
+			_, err := fmt.Sscanf(s, "%dx%d", &res.Width, &res.Height)
+			return res, err
+		})
+
+		testOut := struct {
+			Size *size `copy:"size"`
+		}{}
+
+		if err := Decode(map[string]interface{}{"size": "640x480"}, &testOut, "copy", 0, WithHooks(sizes)); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if testOut.Size == nil || *testOut.Size != (size{Width: 640, Height: 480}) {
+			t.Errorf("Decode() = %+v", testOut.Size)
+		}
+	})
+
+	t.Run("test hook result decoded further", func(t *testing.T) {
+		chained := NewHooks()
+		testOut := struct {
+			Size int `copy:"size"`
+		}{}
+
+		chained.Register(typeOf[string](), typeOf[int](), func(data interface{}) (interface{}, error) {
+			return int64(len(data.(string))), nil
+		})
+
+		if err := Decode(map[string]interface{}{"size": "abcd"}, &testOut, "copy", 0, WithHooks(chained)); err != nil || testOut.Size != 4 {
+			t.Errorf("Decode() = %v, error = %v", testOut.Size, err)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package decode
+
+import (
+	"reflect"
+)
+
+// WithMerge is the option form of DecoderMerge.
+func WithMerge() Option {
+	return WithFlags(DecoderMerge)
+}
+
+// WithSkipZero is the option form of DecoderSkipZero.
+func WithSkipZero() Option {
+	return WithFlags(DecoderSkipZero)
+}
+
+// WithAppendSlices is the option form of DecoderAppendSlices.
+func WithAppendSlices() Option {
+	return WithFlags(DecoderAppendSlices)
+}
+
+// skipZero reports whether a zero source value must leave the destination unchanged.
+func (d *decoder) skipZero(source reflect.Value) bool {
+	if d.flag&DecoderSkipZero == 0 {
+		return false
+	}
+
+	if source.Kind() == reflect.Interface {
+		source = source.Elem()
+	}
+
+	return !source.IsValid() || source.IsZero()
+}
+
+// mergeTarget returns a settable copy of the existing destination value when
+// DecoderMerge is set and both values are maps or structs, so that decoding
+// into it keeps the keys and fields absent from the source.
+func (d *decoder) mergeTarget(existing reflect.Value, source reflect.Value) (reflect.Value, bool) {
+	if d.flag&DecoderMerge == 0 || !existing.IsValid() {
+		return reflect.Value{}, false
+	}
+
+	if existing.Kind() == reflect.Interface {
+		existing = existing.Elem()
+	}
+
+	if source.Kind() == reflect.Interface {
+		source = source.Elem()
+	}
+
+	if !mergeable(existing) || !mergeable(source) {
+		return reflect.Value{}, false
+	}
+
+	target := reflect.New(existing.Type()).Elem()
+	target.Set(existing)
+
+	return target, true
+}
+
+func mergeable(v reflect.Value) bool {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return false
+		}
+		v = v.Elem()
+	}
+
+	return v.Kind() == reflect.Map && !v.IsNil() || v.Kind() == reflect.Struct
+}
@@ -0,0 +1,165 @@
+package decode
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeMerge(t *testing.T) {
+	type address struct {
+		City   string `json:"city"`
+		Street string `json:"street"`
+	}
+
+	type profile struct {
+		Name    string                 `json:"name"`
+		Age     int                    `json:"age" default:"18"`
+		Active  bool                   `json:"active"`
+		Tags    []string               `json:"tags"`
+		Address address                `json:"address"`
+		Home    *address               `json:"home"`
+		Extra   map[string]interface{} `json:"extra"`
+		Raw     interface{}            `json:"raw"`
+	}
+
+	current := func() profile {
+		return profile{
+			Name:    "John",
+			Active:  true,
+			Tags:    []string{"a"},
+			Address: address{City: "Berlin", Street: "Main"},
+			Home:    &address{City: "Paris", Street: "Rue"},
+			Extra: map[string]interface{}{
+				"theme": "dark",
+				"limits": map[string]interface{}{
+					"cpu": 1,
+					"mem": 2,
+				},
+			},
+			Raw: map[string]interface{}{"a": 1},
+		}
+	}
+
+	t.Run("test patch only present fields", func(t *testing.T) {
+		testIn := map[string]interface{}{
+			"age":     30,
+			"address": map[string]interface{}{"city": "Munich"},
+			"home":    map[string]interface{}{"street": "Avenue"},
+			"extra": map[string]interface{}{
+				"limits": map[string]interface{}{"cpu": 4},
+			},
+			"raw":  map[string]interface{}{"b": 2},
+			"tags": []interface{}{"b"},
+		}
+		testOut := current()
+
+		if err := Decode(testIn, &testOut, "json", DecoderMerge); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want := current()
+		want.Age = 30
+		want.Tags = []string{"b"}
+		want.Address.City = "Munich"
+		want.Home.Street = "Avenue"
+		want.Extra["limits"] = map[string]interface{}{"cpu": 4, "mem": 2}
+		want.Raw = map[string]interface{}{"a": 1, "b": 2}
+
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("Decode() = %+v, want %+v", testOut, want)
+		}
+	})
+
+	t.Run("test without merge nested maps are replaced", func(t *testing.T) {
+		testIn := map[string]interface{}{
+			"extra": map[string]interface{}{
+				"limits": map[string]interface{}{"cpu": 4},
+			},
+		}
+		testOut := current()
+
+		if err := Decode(testIn, &testOut, "json", 0); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want := map[string]interface{}{"cpu": 4}
+		if !reflect.DeepEqual(testOut.Extra["limits"], want) {
+			t.Errorf("Decode() = %v, want %v", testOut.Extra["limits"], want)
+		}
+	})
+
+	t.Run("test merge skips defaults", func(t *testing.T) {
+		testOut := profile{}
+
+		if err := Decode(map[string]interface{}{"name": "John"}, &testOut, "json", 0, WithMerge()); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if testOut.Age != 0 {
+			t.Errorf("Decode() Age = %d, want 0", testOut.Age)
+		}
+	})
+
+	t.Run("test null clears field", func(t *testing.T) {
+		testOut := current()
+
+		if err := Decode(map[string]interface{}{"home": nil}, &testOut, "json", DecoderMerge); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if testOut.Home != nil {
+			t.Errorf("Decode() Home = %v, want nil", testOut.Home)
+		}
+	})
+
+	t.Run("test skip zero", func(t *testing.T) {
+		testIn := map[string]interface{}{
+			"name":   "",
+			"active": false,
+			"home":   nil,
+			"age":    30,
+			"extra":  map[string]interface{}{"theme": ""},
+		}
+		testOut := current()
+
+		if err := Decode(testIn, &testOut, "json", DecoderMerge, WithSkipZero()); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want := current()
+		want.Age = 30
+
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("Decode() = %+v, want %+v", testOut, want)
+		}
+	})
+
+	t.Run("test skip zero struct to struct", func(t *testing.T) {
+		testOut := current()
+
+		if err := Decode(profile{Name: "Jane"}, &testOut, "json", DecoderMerge|DecoderSkipZero); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want := current()
+		want.Name = "Jane"
+
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("Decode() = %+v, want %+v", testOut, want)
+		}
+	})
+
+	t.Run("test append slices", func(t *testing.T) {
+		testOut := current()
+
+		err := Decode(map[string]interface{}{"tags": []interface{}{"b", 3}}, &testOut, "json", 0, WithMerge(), WithAppendSlices())
+		if err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want := []string{"a", "b", "3"}
+		if !reflect.DeepEqual(testOut.Tags, want) {
+			t.Errorf("Decode() Tags = %v, want %v", testOut.Tags, want)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package decode
+
+import (
+	"sort"
+)
+
+// Metadata reports which keys took part in decoding.
+type Metadata struct {
+	Keys   []string // Source keys decoded into the destination
+	Unused []string // Source keys without a matching destination field
+	Unset  []string // Destination fields absent from the source
+	Fields []string // Destination fields set from the source by Go field path, e.g. DB.Host
+}
+
+// WithMetadata fills metadata with the decoded, unused and unset keys.
+// Pass it to Decoder.Decode when the Decoder is shared between goroutines.
+func WithMetadata(metadata *Metadata) Option {
+	return func(d *Decoder) {
+		d.meta = metadata
+	}
+}
+
+func (d *decoder) markKey(path string) {
+	if d.meta != nil {
+		d.meta.Keys = append(d.meta.Keys, path)
+		d.meta.Fields = append(d.meta.Fields, d.dstPath)
+	}
+}
+
+// keyCount returns the number of keys marked as used so far.
+func (d *decoder) keyCount() int {
+	if d.meta == nil {
+		return 0
+	}
+
+	return len(d.meta.Keys)
+}
+
+func (d *decoder) markUnused(path string) {
+	if d.meta != nil {
+		d.meta.Unused = append(d.meta.Unused, path)
+	}
+}
+
+// markUnset records destination fields whose names are missing in found.
+func (d *decoder) markUnset(path string, dstFields *fieldPlan, found map[string]bool) {
+	if d.meta == nil {
+		return
+	}
+
+	for _, f := range dstFields.fields {
+		if !found[f.name] {
+			d.meta.Unset = append(d.meta.Unset, joinPath(path, f.name))
+		}
+	}
+}
+
+func (m *Metadata) sort() {
+	sort.Strings(m.Keys)
+	sort.Strings(m.Unused)
+	sort.Strings(m.Unset)
+	sort.Strings(m.Fields)
+}
@@ -0,0 +1,112 @@
+package decode
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeMetadata(t *testing.T) {
+	type nested struct {
+		Field string `copy:"field"`
+		Port  int    `copy:"port"`
+	}
+
+	type testStruct struct {
+		Name   string `copy:"name"`
+		Age    int    `copy:"age"`
+		Nested nested `copy:"nested"`
+		Other  nested `copy:"other"`
+	}
+
+	t.Run("test map to struct metadata", func(t *testing.T) {
+		testIn := map[string]interface{}{
+			"name":   "John",
+			"nmae":   "typo",
+			"nested": map[string]interface{}{"field": "value", "prot": 80},
+		}
+		testOut := testStruct{}
+		metadata := Metadata{}
+
+		if err := Decode(testIn, &testOut, "copy", 0, WithMetadata(&metadata)); err != nil {
+			t.Errorf("Decode() error = %v", err)
+		}
+
+		want := Metadata{
+			Keys:   []string{"name", "nested", "nested.field"},
+			Unused: []string{"nested.prot", "nmae"},
+			Unset:  []string{"age", "nested.port", "other"},
+			Fields: []string{"Name", "Nested", "Nested.Field"},
+		}
+
+		if !reflect.DeepEqual(metadata, want) {
+			t.Errorf("Decode() metadata = %v, want %v", metadata, want)
+		}
+	})
+
+	t.Run("test struct to struct metadata", func(t *testing.T) {
+		testIn := struct {
+			Name  string `copy:"name"`
+			Email string `copy:"email"`
+		}{Name: "John", Email: "john@example.com"}
+		testOut := struct {
+			Name string `copy:"name"`
+			Age  int    `copy:"age"`
+		}{}
+		metadata := Metadata{}
+
+		if err := Decode(testIn, &testOut, "copy", 0, WithMetadata(&metadata)); err != nil {
+			t.Errorf("Decode() error = %v", err)
+		}
+
+		want := Metadata{
+			Keys:   []string{"name"},
+			Unused: []string{"email"},
+			Unset:  []string{"age"},
+			Fields: []string{"Name"},
+		}
+
+		if !reflect.DeepEqual(metadata, want) {
+			t.Errorf("Decode() metadata = %v, want %v", metadata, want)
+		}
+	})
+
+	t.Run("test slice field paths", func(t *testing.T) {
+		type server struct {
+			Host string `json:"host"`
+		}
+		testOut := struct {
+			Servers []server `json:"servers"`
+		}{}
+		metadata := Metadata{}
+
+		testIn := map[string]interface{}{"servers": []interface{}{map[string]interface{}{"host": "a"}}}
+		if err := Decode(testIn, &testOut, "json", 0, WithMetadata(&metadata)); err != nil {
+			t.Errorf("Decode() error = %v", err)
+		}
+
+		want := []string{"Servers", "Servers[0].Host"}
+		if !reflect.DeepEqual(metadata.Fields, want) {
+			t.Errorf("Decode() metadata fields = %v, want %v", metadata.Fields, want)
+		}
+	})
+
+	t.Run("test squashed field paths", func(t *testing.T) {
+		type base struct {
+			ID int `json:"id"`
+		}
+		testOut := struct {
+			B    base   `json:",squash"`
+			Name string `json:"name"`
+		}{}
+		metadata := Metadata{}
+
+		if err := Decode(map[string]interface{}{"id": 3, "name": "a"}, &testOut, "json", 0, WithMetadata(&metadata)); err != nil {
+			t.Errorf("Decode() error = %v", err)
+		}
+
+		want := []string{"B.ID", "Name"}
+		if !reflect.DeepEqual(metadata.Fields, want) {
+			t.Errorf("Decode() metadata fields = %v, want %v", metadata.Fields, want)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package decode
+
+import (
+	"strings"
+)
+
+// MatchName reports whether a source key matches the name of a destination field.
+type MatchName func(key, name string) bool
+
+// WithMatchName matches source keys to destination fields with match when there is no exact match.
+func WithMatchName(match MatchName) Option {
+	return func(d *Decoder) {
+		d.matchName = match
+	}
+}
+
+// WithCaseInsensitive is the option form of DecoderCaseInsensitive.
+func WithCaseInsensitive() Option {
+	return WithFlags(DecoderCaseInsensitive)
+}
+
+// WithMatchConventions is the option form of DecoderMatchConventions.
+func WithMatchConventions() Option {
+	return WithFlags(DecoderMatchConventions)
+}
+
+// normalizeName lowercases name and drops word separators, so UserID, userId,
+// user_id, user-id and USER_ID have the same form.
+func normalizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(name))
+}
+
+// field returns the destination field for a source key. An exact match is
+// preferred, then the custom MatchName, case-insensitive and convention matches.
+func (d *decoder) field(plan *fieldPlan, key string) (*structField, bool) {
+	if f, ok := plan.byName[key]; ok {
+		return f, true
+	}
+
+	if d.matchName != nil {
+		for i := range plan.fields {
+			if d.matchName(key, plan.fields[i].name) {
+				return &plan.fields[i], true
+			}
+		}
+	}
+
+	if d.flag&DecoderCaseInsensitive != 0 {
+		if f, ok := plan.byFold[strings.ToLower(key)]; ok {
+			return f, true
+		}
+	}
+
+	if d.flag&DecoderMatchConventions != 0 {
+		if f, ok := plan.byConvention[normalizeName(key)]; ok {
+			return f, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,121 @@
+package decode
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeNameMatching(t *testing.T) {
+	type user struct {
+		UserID    int
+		FirstName string
+		Email     string `copy:"email"`
+	}
+
+	t.Run("test exact match only by default", func(t *testing.T) {
+		testOut := user{}
+
+		if err := Decode(map[string]interface{}{"user_id": 1, "EMAIL": "a@b.c"}, &testOut, "", 0); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if !reflect.DeepEqual(testOut, user{}) {
+			t.Errorf("Decode() = %+v, want zero value", testOut)
+		}
+	})
+
+	t.Run("test case insensitive", func(t *testing.T) {
+		testIn := map[string]interface{}{"userid": 1, "FIRSTNAME": "John", "Email": "a@b.c"}
+		testOut := user{}
+
+		if err := Decode(testIn, &testOut, "copy", 0, WithCaseInsensitive()); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want := user{Email: "a@b.c"}
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("Decode() = %+v, want %+v", testOut, want)
+		}
+
+		testOut = user{}
+		if err := Decode(testIn, &testOut, "", DecoderCaseInsensitive); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want = user{UserID: 1, FirstName: "John", Email: "a@b.c"}
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("Decode() = %+v, want %+v", testOut, want)
+		}
+	})
+
+	t.Run("test naming conventions", func(t *testing.T) {
+		for _, keys := range [][2]string{
+			{"user_id", "first_name"},
+			{"userId", "firstName"},
+			{"user-id", "first-name"},
+			{"USER_ID", "FIRST_NAME"},
+		} {
+			testOut := user{}
+			testIn := map[string]interface{}{keys[0]: 1, keys[1]: "John"}
+
+			if err := Decode(testIn, &testOut, "", 0, WithMatchConventions()); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			want := user{UserID: 1, FirstName: "John"}
+			if !reflect.DeepEqual(testOut, want) {
+				t.Errorf("Decode(%v) = %+v, want %+v", keys, testOut, want)
+			}
+		}
+	})
+
+	t.Run("test naming conventions skip tagged fields", func(t *testing.T) {
+		type tagged struct {
+			UserID int `copy:"uid"`
+		}
+		testOut := tagged{}
+
+		err := Decode(map[string]interface{}{"user_id": 1}, &testOut, "copy", DecoderMatchConventions|DecoderStrongFoundDst)
+		if !errors.Is(err, ErrorDstNotFound) {
+			t.Errorf("Decode() error = %v, want %v", err, ErrorDstNotFound)
+		}
+	})
+
+	t.Run("test exact match wins", func(t *testing.T) {
+		type pair struct {
+			Name string
+			NAME string
+		}
+		testOut := pair{}
+
+		if err := Decode(map[string]interface{}{"NAME": "upper"}, &testOut, "", DecoderCaseInsensitive); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if testOut.NAME != "upper" || testOut.Name != "" {
+			t.Errorf("Decode() = %+v, want NAME set", testOut)
+		}
+	})
+
+	t.Run("test custom match name", func(t *testing.T) {
+		match := func(key, name string) bool {
+			return strings.EqualFold(strings.TrimPrefix(key, "x_"), name)
+		}
+		testOut := user{}
+		var md Metadata
+
+		if err := Decode(map[string]interface{}{"x_userid": 1}, &testOut, "", 0, WithMatchName(match), WithMetadata(&md)); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if testOut.UserID != 1 {
+			t.Errorf("Decode() = %+v, want UserID 1", testOut)
+		}
+
+		if !reflect.DeepEqual(md.Unset, []string{"Email", "FirstName"}) {
+			t.Errorf("Metadata.Unset = %v, want [Email FirstName]", md.Unset)
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package decode
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// convert converts source into targetType, checking numeric conversions when
+// DecoderStrictNumeric is set.
+func (d *decoder) convert(source reflect.Value, targetType reflect.Type) (reflect.Value, error) {
+	if err := d.checkNumeric(source, targetType); err != nil {
+		return reflect.Value{}, err
+	}
+
+	return convertBasicTypes(source, targetType)
+}
+
+// checkNumeric reports overflow, precision loss and NaN/Inf values of a numeric
+// conversion when DecoderStrictNumeric is set.
+func (d *decoder) checkNumeric(source reflect.Value, targetType reflect.Type) error {
+	if d.flag&DecoderStrictNumeric == 0 {
+		return nil
+	}
+
+	if source.Kind() == reflect.Interface {
+		source = source.Elem()
+	}
+
+	if source.Kind() == reflect.String {
+		if _, ok := targetType.MethodByName("Nanoseconds"); ok {
+			return nil
+		} else if hasParse(targetType) {
+			return nil
+		}
+
+		source = parseNumber(source.String())
+	}
+
+	switch targetType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch source.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if reflect.Zero(targetType).OverflowInt(source.Int()) {
+				return overflowError(source, targetType)
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if source.Uint() > math.MaxInt64 || reflect.Zero(targetType).OverflowInt(int64(source.Uint())) {
+				return overflowError(source, targetType)
+			}
+		case reflect.Float32, reflect.Float64:
+			return checkFloatToInt(source, source.Float(), targetType)
+		case reflect.Complex64, reflect.Complex128:
+			if imag(source.Complex()) != 0 {
+				return precisionError(source, targetType)
+			}
+			return checkFloatToInt(source, real(source.Complex()), targetType)
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		switch source.Kind() {
+		case reflect.Float32, reflect.Float64:
+			if f := source.Float(); math.IsNaN(f) || math.IsInf(f, 0) {
+				return overflowError(source, targetType)
+			} else if f != math.Trunc(f) {
+				return precisionError(source, targetType)
+			}
+		case reflect.Complex64, reflect.Complex128:
+			if c := source.Complex(); imag(c) != 0 || real(c) != math.Trunc(real(c)) {
+				return precisionError(source, targetType)
+			}
+		}
+
+	case reflect.Float32, reflect.Float64:
+		switch source.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if f := reflect.ValueOf(source.Int()).Convert(targetType).Float(); f >= math.MaxInt64 || int64(f) != source.Int() {
+				return precisionError(source, targetType)
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if f := reflect.ValueOf(source.Uint()).Convert(targetType).Float(); f >= math.MaxUint64 || uint64(f) != source.Uint() {
+				return precisionError(source, targetType)
+			}
+		case reflect.Float32, reflect.Float64:
+			if f := source.Float(); math.IsNaN(f) || math.IsInf(f, 0) || reflect.Zero(targetType).OverflowFloat(f) {
+				return overflowError(source, targetType)
+			}
+		case reflect.Complex64, reflect.Complex128:
+			if imag(source.Complex()) != 0 {
+				return precisionError(source, targetType)
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkFloatToInt(source reflect.Value, f float64, targetType reflect.Type) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return overflowError(source, targetType)
+	} else if f != math.Trunc(f) {
+		return precisionError(source, targetType)
+	} else if reflect.Zero(targetType).OverflowInt(int64(f)) {
+		return overflowError(source, targetType)
+	}
+
+	return nil
+}
+
+// parseNumber parses str as an integer or a float, returning str itself otherwise.
+func parseNumber(str string) reflect.Value {
+	if i, err := strconv.ParseInt(str, 10, 64); err == nil {
+		return reflect.ValueOf(i)
+	} else if u, err := strconv.ParseUint(str, 10, 64); err == nil {
+		return reflect.ValueOf(u)
+	} else if f, err := strconv.ParseFloat(str, 64); err == nil {
+		return reflect.ValueOf(f)
+	}
+
+	return reflect.ValueOf(str)
+}
+
+func precisionError(source reflect.Value, targetType reflect.Type) error {
+	return fmt.Errorf("%w: %v loses precision in %s", ErrorPrecisionLoss, source.Interface(), targetType)
+}
@@ -0,0 +1,82 @@
+package decode
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestDecodeStrictNumeric(t *testing.T) {
+	type testStruct struct {
+		Small int16   `copy:"small"`
+		Int   int     `copy:"int"`
+		Uint  uint    `copy:"uint"`
+		Float float32 `copy:"float"`
+		Exact float64 `copy:"exact"`
+	}
+
+	tests := []struct {
+		name string
+		key  string
+		in   interface{}
+		want error
+	}{
+		{name: "int overflow", key: "small", in: 70000, want: ErrorOverflow},
+		{name: "int64 overflow", key: "small", in: int64(-40000), want: ErrorOverflow},
+		{name: "uint overflow", key: "small", in: uint(40000), want: ErrorOverflow},
+		{name: "string overflow", key: "small", in: "70000", want: ErrorOverflow},
+		{name: "fractional float", key: "int", in: 3.7, want: ErrorPrecisionLoss},
+		{name: "fractional string", key: "int", in: "3.7", want: ErrorPrecisionLoss},
+		{name: "fractional float to uint", key: "uint", in: 1.5, want: ErrorPrecisionLoss},
+		{name: "NaN", key: "int", in: math.NaN(), want: ErrorOverflow},
+		{name: "Inf", key: "int", in: math.Inf(1), want: ErrorOverflow},
+		{name: "NaN float", key: "exact", in: math.NaN(), want: ErrorOverflow},
+		{name: "float32 overflow", key: "float", in: 1e300, want: ErrorOverflow},
+		{name: "int to float precision", key: "exact", in: int64(1<<53 + 1), want: ErrorPrecisionLoss},
+	}
+
+	for _, tt := range tests {
+		t.Run("test "+tt.name, func(t *testing.T) {
+			testOut := testStruct{}
+
+			err := Decode(map[string]interface{}{tt.key: tt.in}, &testOut, "copy", DecoderStrictNumeric)
+
+			var decodeErr *DecodeError
+			if !errors.Is(err, tt.want) || !errors.As(err, &decodeErr) || decodeErr.Path != tt.key {
+				t.Errorf("Decode() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	t.Run("test valid values", func(t *testing.T) {
+		testIn := map[string]interface{}{
+			"small": "-32768",
+			"int":   float64(42),
+			"uint":  uint8(7),
+			"float": 1.5,
+			"exact": int64(1 << 53),
+		}
+		testOut := testStruct{}
+
+		if err := Decode(testIn, &testOut, "copy", DecoderStrictNumeric); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want := testStruct{Small: -32768, Int: 42, Uint: 7, Float: 1.5, Exact: 1 << 53}
+		if testOut != want {
+			t.Errorf("Decode() = %+v, want %+v", testOut, want)
+		}
+	})
+
+	t.Run("test lenient truncates", func(t *testing.T) {
+		testOut := testStruct{}
+
+		if err := Decode(map[string]interface{}{"small": 70000, "int": 3.7}, &testOut, "copy", 0); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if testOut.Small != int16(70000-65536) || testOut.Int != 3 {
+			t.Errorf("Decode() = %+v", testOut)
+		}
+	})
+}
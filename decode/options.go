@@ -0,0 +1,71 @@
+package decode
+
+import (
+	"strings"
+)
+
+// Option configures a Decoder or a single Decode call.
+type Option func(*Decoder)
+
+// WithTag sets the struct tag used to match fields, overriding the tag argument of Decode.
+// Fields without the tag are skipped.
+func WithTag(tag string) Option {
+	return func(d *Decoder) {
+		d.tags = fieldTags{chain: tag}
+	}
+}
+
+// WithTags names fields by the first of tags present on the field, falling back to
+// the field name when none is, e.g. WithTags("mapstructure", "json").
+func WithTags(tags ...string) Option {
+	return func(d *Decoder) {
+		d.tags = fieldTags{chain: strings.Join(tags, ","), fallback: true}
+	}
+}
+
+// WithFlags adds decoder flags to the ones passed to Decode.
+func WithFlags(flag DecoderFlag) Option {
+	return func(d *Decoder) {
+		d.flag |= flag
+	}
+}
+
+// WithStrict fails on source keys without destination and on lossy numeric conversions.
+func WithStrict() Option {
+	return WithFlags(DecoderStrongFoundDst | DecoderStrictNumeric)
+}
+
+// WithStrongFoundDst is the option form of DecoderStrongFoundDst.
+func WithStrongFoundDst() Option {
+	return WithFlags(DecoderStrongFoundDst)
+}
+
+// WithStrongType is the option form of DecoderStrongType.
+func WithStrongType() Option {
+	return WithFlags(DecoderStrongType)
+}
+
+// WithUnwrapStructs is the option form of DecoderUnwrapStructToMap.
+func WithUnwrapStructs() Option {
+	return WithFlags(DecoderUnwrapStructToMap)
+}
+
+// WithCollectErrors is the option form of DecoderCollectErrors.
+func WithCollectErrors() Option {
+	return WithFlags(DecoderCollectErrors)
+}
+
+// WithValidate is the option form of DecoderValidate.
+func WithValidate() Option {
+	return WithFlags(DecoderValidate)
+}
+
+// WithWeaklyTyped is the option form of DecoderWeaklyTyped.
+func WithWeaklyTyped() Option {
+	return WithFlags(DecoderWeaklyTyped)
+}
+
+// WithStrictNumeric is the option form of DecoderStrictNumeric.
+func WithStrictNumeric() Option {
+	return WithFlags(DecoderStrictNumeric)
+}
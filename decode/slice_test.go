@@ -0,0 +1,118 @@
+package decode
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeSlice(t *testing.T) {
+	type tls struct {
+		Cert string `copy:"cert"`
+	}
+
+	type server struct {
+		Host string `copy:"host"`
+		Port int    `copy:"port"`
+		TLS  *tls   `copy:"tls"`
+	}
+
+	t.Run("test slice of maps to slice of structs", func(t *testing.T) {
+		testIn := map[string]interface{}{
+			"servers": []interface{}{
+				map[string]interface{}{"host": "a", "port": "80"},
+				map[string]interface{}{"host": "b", "port": 443, "tls": map[string]interface{}{"cert": "b.pem"}},
+			},
+		}
+		testOut := struct {
+			Servers []server `copy:"servers"`
+		}{}
+
+		if err := Decode(testIn, &testOut, "copy", 0); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want := []server{{Host: "a", Port: 80}, {Host: "b", Port: 443, TLS: &tls{Cert: "b.pem"}}}
+		if !reflect.DeepEqual(testOut.Servers, want) {
+			t.Errorf("Decode() = %+v, want %+v", testOut.Servers, want)
+		}
+	})
+
+	t.Run("test slice of structs to slice of maps", func(t *testing.T) {
+		testIn := []server{{Host: "a", Port: 80}}
+		var testOut []map[string]interface{}
+
+		if err := Decode(testIn, &testOut, "copy", 0); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want := []map[string]interface{}{{"host": "a", "port": 80, "tls": (*tls)(nil)}}
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("Decode() = %#v, want %#v", testOut, want)
+		}
+	})
+
+	t.Run("test nested slices", func(t *testing.T) {
+		testIn := []interface{}{[]interface{}{1, "2"}, []interface{}{3.0}}
+		var testOut [][]int
+
+		if err := Decode(testIn, &testOut, "copy", 0); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want := [][]int{{1, 2}, {3}}
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("Decode() = %v, want %v", testOut, want)
+		}
+	})
+
+	t.Run("test arrays", func(t *testing.T) {
+		var testOut [3]string
+		if err := Decode([]interface{}{1, "b"}, &testOut, "copy", 0); err != nil || testOut != [3]string{"1", "b", ""} {
+			t.Errorf("Decode() = %v, error = %v", testOut, err)
+		}
+
+		var testOutSlice []int
+		if err := Decode([2]string{"1", "2"}, &testOutSlice, "copy", 0); err != nil || !reflect.DeepEqual(testOutSlice, []int{1, 2}) {
+			t.Errorf("Decode() = %v, error = %v", testOutSlice, err)
+		}
+
+		var testOutSmall [1]int
+		if err := Decode([]int{1, 2}, &testOutSmall, "copy", 0); !errors.Is(err, ErrorTypeMismatch) {
+			t.Errorf("Decode() error = %v", err)
+		}
+	})
+
+	t.Run("test element error path", func(t *testing.T) {
+		testIn := map[string]interface{}{
+			"servers": []interface{}{
+				map[string]interface{}{"host": "a"},
+				map[string]interface{}{"host": "b", "port": "http"},
+			},
+		}
+		testOut := struct {
+			Servers []server `copy:"servers"`
+		}{}
+
+		err := Decode(testIn, &testOut, "copy", 0)
+
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Path != "servers[1].port" {
+			t.Errorf("Decode() error = %v", err)
+		}
+	})
+
+	t.Run("test slice to interface", func(t *testing.T) {
+		var testOut interface{}
+
+		testIn := []int{1, 2}
+		if err := Decode(testIn, &testOut, "copy", 0); err != nil || !reflect.DeepEqual(testOut, []int{1, 2}) {
+			t.Errorf("Decode() = %v, error = %v", testOut, err)
+		}
+
+		testIn[0] = 5
+		if testOut.([]int)[0] != 1 {
+			t.Errorf("Decode() = %v, want a copy", testOut)
+		}
+	})
+}
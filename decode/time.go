@@ -0,0 +1,135 @@
+package decode
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// DefaultTimeLayouts are tried in order when decoding strings into time.Time.
+var DefaultTimeLayouts = []string{
+	time.RFC3339Nano,
+	time.DateTime,
+	time.DateOnly,
+}
+
+// WithTimeLayouts sets the layouts tried when decoding strings into time.Time.
+// The first layout is used to format time.Time into strings.
+func WithTimeLayouts(layouts ...string) Option {
+	return func(d *Decoder) {
+		d.timeLayouts = layouts
+	}
+}
+
+// WithTimeLocation sets the location for layouts without a zone, unix timestamps
+// and formatted times.
+func WithTimeLocation(location *time.Location) Option {
+	return func(d *Decoder) {
+		d.timeLocation = location
+	}
+}
+
+// WithTimeUnit sets the unit of numeric unix timestamps, time.Second by default.
+// Use time.Millisecond for unix milliseconds.
+func WithTimeUnit(unit time.Duration) Option {
+	return func(d *Decoder) {
+		d.timeUnit = unit
+	}
+}
+
+// decodeTime converts strings and unix timestamps into time.Time and time.Time into
+// strings and numbers. It reports whether the value was handled.
+func (d *decoder) decodeTime(source reflect.Value, destination reflect.Value, path string) (bool, error) {
+	if !source.IsValid() || !destination.IsValid() {
+		return false, nil
+	}
+
+	if destination.Type() == timeType {
+		t, err := d.parseTime(source)
+		if errors.Is(err, ErrorTypeMismatch) {
+			return false, nil
+		} else if err != nil {
+			return true, d.fail(newDecodeError(path, source, destination.Type(), ErrorTypeMismatch, err))
+		}
+
+		destination.Set(reflect.ValueOf(t))
+		return true, nil
+	}
+
+	if source.Type() == timeType {
+		t := source.Interface().(time.Time)
+		if d.timeLocation != nil {
+			t = t.In(d.timeLocation)
+		}
+
+		switch destination.Kind() {
+		case reflect.String:
+			destination.SetString(t.Format(d.layouts()[0]))
+			return true, nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			destination.SetInt(t.UnixNano() / int64(d.unit()))
+			return true, nil
+		case reflect.Float32, reflect.Float64:
+			destination.SetFloat(float64(t.UnixNano()) / float64(d.unit()))
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (d *decoder) parseTime(source reflect.Value) (time.Time, error) {
+	location := d.timeLocation
+	if location == nil {
+		location = time.UTC
+	}
+
+	switch source.Kind() {
+	case reflect.String:
+		var err error
+		for _, layout := range d.layouts() {
+			var t time.Time
+			if t, err = time.ParseInLocation(layout, source.String(), location); err == nil {
+				return t, nil
+			}
+		}
+
+		if unix, intErr := strconv.ParseInt(source.String(), 10, 64); intErr == nil {
+			return time.Unix(0, unix*int64(d.unit())).In(location), nil
+		}
+
+		return time.Time{}, err
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return time.Unix(0, source.Int()*int64(d.unit())).In(location), nil
+
+	case reflect.Float32, reflect.Float64:
+		return time.Unix(0, int64(source.Float()*float64(d.unit()))).In(location), nil
+
+	case reflect.Struct:
+		if source.Type() == timeType {
+			return source.Interface().(time.Time), nil
+		}
+	}
+
+	return time.Time{}, ErrorTypeMismatch
+}
+
+func (d *decoder) layouts() []string {
+	if len(d.timeLayouts) == 0 {
+		return DefaultTimeLayouts
+	}
+
+	return d.timeLayouts
+}
+
+func (d *decoder) unit() time.Duration {
+	if d.timeUnit <= 0 {
+		return time.Second
+	}
+
+	return d.timeUnit
+}
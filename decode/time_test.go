@@ -0,0 +1,91 @@
+package decode
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDecodeTime(t *testing.T) {
+	type testStruct struct {
+		Created time.Time  `copy:"created"`
+		Updated *time.Time `copy:"updated"`
+	}
+
+	want := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		in   interface{}
+		opts []Option
+		want time.Time
+	}{
+		{name: "RFC3339", in: "2024-05-01T10:00:00Z", want: want},
+		{name: "date time", in: "2024-05-01 10:00:00", want: want},
+		{name: "date only", in: "2024-05-01", want: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "unix seconds", in: want.Unix(), want: want},
+		{name: "unix seconds float", in: float64(want.Unix()), want: want},
+		{name: "unix seconds string", in: "1714557600", want: want},
+		{name: "unix millis", in: want.UnixMilli(), opts: []Option{WithTimeUnit(time.Millisecond)}, want: want},
+		{name: "custom layout", in: "01.05.2024 10:00", opts: []Option{WithTimeLayouts("02.01.2006 15:04")}, want: want},
+		{
+			name: "location",
+			in:   "2024-05-01 13:00:00",
+			opts: []Option{WithTimeLocation(time.FixedZone("MSK", 3*60*60))},
+			want: want,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testOut := testStruct{}
+
+			if err := Decode(map[string]interface{}{"created": tt.in, "updated": tt.in}, &testOut, "copy", 0, tt.opts...); err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			if !testOut.Created.Equal(tt.want) || testOut.Updated == nil || !testOut.Updated.Equal(tt.want) {
+				t.Errorf("Decode() = %v, want %v", testOut.Created, tt.want)
+			}
+		})
+	}
+
+	t.Run("test invalid time", func(t *testing.T) {
+		testOut := testStruct{}
+
+		err := Decode(map[string]interface{}{"created": "yesterday"}, &testOut, "copy", 0)
+
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Path != "created" {
+			t.Errorf("Decode() error = %v", err)
+		}
+	})
+
+	t.Run("test time to map", func(t *testing.T) {
+		testIn := struct {
+			Created time.Time `copy:"created"`
+		}{Created: want}
+
+		testOut := map[string]string{}
+		if err := Decode(testIn, &testOut, "copy", 0); err != nil || testOut["created"] != "2024-05-01T10:00:00Z" {
+			t.Errorf("Decode() = %v, error = %v", testOut, err)
+		}
+
+		testOutInt := map[string]int64{}
+		if err := Decode(testIn, &testOutInt, "copy", 0, WithTimeUnit(time.Millisecond)); err != nil ||
+			testOutInt["created"] != want.UnixMilli() {
+			t.Errorf("Decode() = %v, error = %v", testOutInt, err)
+		}
+
+		testOutAny := map[string]interface{}{}
+		if err := Decode(testIn, &testOutAny, "copy", 0); err != nil || !testOutAny["created"].(time.Time).Equal(want) {
+			t.Errorf("Decode() = %v, error = %v", testOutAny, err)
+		}
+
+		testOutAny = map[string]interface{}{}
+		if err := Decode(testIn, &testOutAny, "copy", DecoderUnwrapStructToMap, WithTimeLayouts(time.DateOnly)); err != nil ||
+			testOutAny["created"] != "2024-05-01" {
+			t.Errorf("Decode() = %v, error = %v", testOutAny, err)
+		}
+	})
+}
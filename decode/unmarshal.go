@@ -0,0 +1,91 @@
+package decode
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// decodeUnmarshaler decodes strings through encoding.TextUnmarshaler, maps and slices
+// other than []byte through json.Unmarshaler and produces strings through
+// encoding.TextMarshaler. Byte slices are copied as is, e.g. into json.RawMessage.
+// It reports whether the value was handled.
+func (d *decoder) decodeUnmarshaler(source reflect.Value, destination reflect.Value, path string) (bool, error) {
+	if !source.IsValid() || !destination.IsValid() || source.Type() == destination.Type() {
+		return false, nil
+	}
+
+	switch source.Kind() {
+	case reflect.String:
+		if reflect.PointerTo(destination.Type()).Implements(textUnmarshalerType) {
+			target := reflect.New(destination.Type())
+			if err := target.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(source.String())); err != nil {
+				return true, d.fail(newDecodeError(path, source, destination.Type(), ErrorTypeMismatch, err))
+			}
+
+			destination.Set(target.Elem())
+			return true, nil
+		}
+
+	case reflect.Map, reflect.Slice:
+		if (source.Kind() == reflect.Map || isList(source)) &&
+			reflect.PointerTo(destination.Type()).Implements(jsonUnmarshalerType) && source.CanInterface() {
+			data, err := json.Marshal(source.Interface())
+			if err != nil {
+				return true, d.fail(newDecodeError(path, source, destination.Type(), ErrorTypeMismatch, err))
+			}
+
+			target := reflect.New(destination.Type())
+			if err := target.Interface().(json.Unmarshaler).UnmarshalJSON(data); err != nil {
+				return true, d.fail(newDecodeError(path, source, destination.Type(), ErrorTypeMismatch, err))
+			}
+
+			destination.Set(target.Elem())
+			return true, nil
+		}
+	}
+
+	if destination.Kind() == reflect.String {
+		if marshaler, ok := textMarshaler(source); ok {
+			text, err := marshaler.MarshalText()
+			if err != nil {
+				return true, d.fail(newDecodeError(path, source, destination.Type(), ErrorTypeMismatch, err))
+			}
+
+			destination.SetString(string(text))
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// textMarshaler returns the encoding.TextMarshaler implemented by v or its address.
+func textMarshaler(v reflect.Value) (encoding.TextMarshaler, bool) {
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if !v.IsValid() || !v.CanInterface() || v.Kind() == reflect.String {
+		return nil, false
+	}
+
+	if v.Type().Implements(textMarshalerType) {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return nil, false
+		}
+		return v.Interface().(encoding.TextMarshaler), true
+	}
+
+	if v.CanAddr() && v.Addr().Type().Implements(textMarshalerType) {
+		return v.Addr().Interface().(encoding.TextMarshaler), true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,138 @@
+package decode
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type level int
+
+const (
+	levelDebug level = iota
+	levelInfo
+)
+
+func (l level) MarshalText() ([]byte, error) {
+	switch l {
+	case levelDebug:
+		return []byte("debug"), nil
+	case levelInfo:
+		return []byte("info"), nil
+	}
+	return nil, fmt.Errorf("unknown level %d", l)
+}
+
+func (l *level) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "debug":
+		*l = levelDebug
+	case "info":
+		*l = levelInfo
+	default:
+		return fmt.Errorf("unknown level %q", text)
+	}
+	return nil
+}
+
+type point struct {
+	X, Y int
+}
+
+func (p *point) UnmarshalJSON(data []byte) error {
+	var raw []int
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw) != 2 {
+		return errors.New("point needs two coordinates")
+	}
+	p.X, p.Y = raw[0], raw[1]
+	return nil
+}
+
+func TestDecodeUnmarshaler(t *testing.T) {
+	type testStruct struct {
+		Addr  netip.Addr `copy:"addr"`
+		Big   *big.Int   `copy:"big"`
+		Level level      `copy:"level"`
+		Point point      `copy:"point"`
+	}
+
+	t.Run("test text unmarshaler", func(t *testing.T) {
+		testIn := map[string]interface{}{
+			"addr":  "192.168.0.1",
+			"big":   "123456789012345678901234567890",
+			"level": "info",
+			"point": []interface{}{1, 2},
+		}
+		testOut := testStruct{}
+
+		if err := Decode(testIn, &testOut, "copy", 0); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+		if testOut.Addr != netip.MustParseAddr("192.168.0.1") || testOut.Big.Cmp(want) != 0 ||
+			testOut.Level != levelInfo || testOut.Point != (point{X: 1, Y: 2}) {
+			t.Errorf("Decode() = %+v", testOut)
+		}
+	})
+
+	t.Run("test unmarshaler error", func(t *testing.T) {
+		testOut := testStruct{}
+
+		err := Decode(map[string]interface{}{"level": "trace"}, &testOut, "copy", 0)
+
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Path != "level" || !strings.Contains(err.Error(), "unknown level") {
+			t.Errorf("Decode() error = %v", err)
+		}
+	})
+
+	t.Run("test text marshaler to map", func(t *testing.T) {
+		testIn := struct {
+			Addr  netip.Addr `copy:"addr"`
+			Level level      `copy:"level"`
+		}{Addr: netip.MustParseAddr("10.0.0.1"), Level: levelInfo}
+
+		testOut := map[string]string{}
+		if err := Decode(testIn, &testOut, "copy", 0); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want := map[string]string{"addr": "10.0.0.1", "level": "info"}
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("Decode() = %v, want %v", testOut, want)
+		}
+
+		testOutAny := map[string]interface{}{}
+		if err := Decode(testIn, &testOutAny, "copy", DecoderUnwrapStructToMap); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		wantAny := map[string]interface{}{"addr": "10.0.0.1", "level": "info"}
+		if !reflect.DeepEqual(testOutAny, wantAny) {
+			t.Errorf("Decode() = %v, want %v", testOutAny, wantAny)
+		}
+	})
+
+	t.Run("test raw message", func(t *testing.T) {
+		testOut := struct {
+			Raw json.RawMessage `copy:"raw"`
+		}{}
+
+		if err := Decode(map[string]interface{}{"raw": []byte(`{"a":1}`)}, &testOut, "copy", 0); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if string(testOut.Raw) != `{"a":1}` {
+			t.Errorf("Decode() = %s, want %s", testOut.Raw, `{"a":1}`)
+		}
+	})
+}
@@ -0,0 +1,242 @@
+package decode
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const validateTag = "validate"
+
+// validateFields checks the validate tag rules of the destination struct fields.
+// Nested structs absent from the source are validated recursively, the rest were
+// validated while decoding.
+func (d *decoder) validateFields(destination reflect.Value, path string, found map[string]bool) error {
+	if d.flag&DecoderValidate == 0 {
+		return nil
+	}
+
+	for _, f := range fieldsOf(destination.Type(), d.tags).fields {
+		dstField, ok := fieldByIndex(destination, f.index, false)
+		if !ok {
+			continue
+		}
+
+		fieldPath := joinPath(path, f.name)
+
+		if err := d.validateRules(dstField, f.rules, fieldPath); err != nil {
+			return err
+		}
+
+		if !found[f.name] && dstField.Kind() == reflect.Struct {
+			if err := d.validateFields(dstField, fieldPath, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateValue checks the validate tag rules of every struct reachable from v through
+// struct fields, non-nil pointers and interfaces, slice and array elements and map values.
+func (d *decoder) validateValue(v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			return d.validateValue(v.Elem(), path)
+		}
+
+	case reflect.Struct:
+		for _, f := range fieldsOf(v.Type(), d.tags).fields {
+			field, ok := fieldByIndex(v, f.index, false)
+			if !ok {
+				continue
+			}
+
+			fieldPath := joinPath(path, f.name)
+
+			if err := d.validateRules(field, f.rules, fieldPath); err != nil {
+				return err
+			}
+
+			if err := d.validateValue(field, fieldPath); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := d.validateValue(v.Index(i), indexPath(path, i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := d.validateValue(iter.Value(), joinPath(path, keyString(iter.Key()))); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateRules checks the validate tag rules of a single struct field.
+func (d *decoder) validateRules(value reflect.Value, rules []string, path string) error {
+	for _, rule := range rules {
+		if err := validateRule(value, rule); err != nil {
+			if err := d.fail(newDecodeError(path, value, nil, ErrorValidation, err)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// splitRules splits a validate tag by commas, the regexp rule takes the rest of the tag.
+func splitRules(rules string) []string {
+	var res []string
+
+	for rules != "" {
+		if strings.HasPrefix(rules, "regexp=") {
+			return append(res, rules)
+		}
+
+		rule, rest, _ := strings.Cut(rules, ",")
+		if rule != "" {
+			res = append(res, rule)
+		}
+		rules = rest
+	}
+
+	return res
+}
+
+func validateRule(value reflect.Value, rule string) error {
+	name, arg, _ := strings.Cut(rule, "=")
+
+	if name == "required" {
+		if value.IsZero() {
+			return errors.New("required")
+		}
+		return nil
+	}
+
+	if value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	switch name {
+	case "min", "max":
+		cmp, err := compareLimit(value, arg)
+		if err != nil {
+			return fmt.Errorf("%s: %w", rule, err)
+		}
+
+		if (name == "min" && cmp < 0) || (name == "max" && cmp > 0) {
+			return fmt.Errorf("%s: got %v", rule, value.Interface())
+		}
+
+	case "oneof":
+		current := fmt.Sprint(value.Interface())
+		for _, v := range strings.Fields(arg) {
+			if v == current {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: got %v", rule, current)
+
+	case "regexp":
+		if value.Kind() != reflect.String {
+			return fmt.Errorf("%s: %w", rule, ErrorTypeMismatch)
+		}
+
+		re, err := compileRegexp(arg)
+		if err != nil {
+			return fmt.Errorf("%s: %w", rule, err)
+		}
+
+		if !re.MatchString(value.String()) {
+			return fmt.Errorf("%s: got %q", rule, value.String())
+		}
+
+	default:
+		return fmt.Errorf("unknown rule %q", rule)
+	}
+
+	return nil
+}
+
+var regexpCache sync.Map
+
+// compileRegexp compiles a validate regexp once and reuses it for later calls.
+func compileRegexp(expr string) (*regexp.Regexp, error) {
+	if re, ok := regexpCache.Load(expr); ok {
+		return re.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	regexpCache.Store(expr, re)
+
+	return re, nil
+}
+
+// compareLimit compares a number with the limit, or the length of strings and collections.
+func compareLimit(value reflect.Value, limit string) (int, error) {
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			return 0, err
+		}
+		return compare(float64(value.Len()), float64(n)), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		converted, err := convertBasicTypes(reflect.ValueOf(limit), value.Type())
+		if err != nil {
+			return 0, err
+		}
+		return compare(float64(value.Int()), float64(converted.Int())), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		converted, err := convertBasicTypes(reflect.ValueOf(limit), value.Type())
+		if err != nil {
+			return 0, err
+		}
+		return compare(float64(value.Uint()), float64(converted.Uint())), nil
+
+	case reflect.Float32, reflect.Float64:
+		converted, err := convertBasicTypes(reflect.ValueOf(limit), value.Type())
+		if err != nil {
+			return 0, err
+		}
+		return compare(value.Float(), converted.Float()), nil
+	}
+
+	return 0, ErrorTypeMismatch
+}
+
+func compare(a float64, b float64) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+
+	return 0
+}
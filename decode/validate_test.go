@@ -0,0 +1,95 @@
+package decode
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestDecodeValidate(t *testing.T) {
+	type tls struct {
+		Cert string `copy:"cert" validate:"required"`
+	}
+
+	type testStruct struct {
+		Name    string        `copy:"name" validate:"required,min=2,max=10"`
+		Port    int           `copy:"port" validate:"min=1,max=65535"`
+		Mode    string        `copy:"mode" validate:"oneof=dev prod"`
+		Email   string        `copy:"email" validate:"regexp=^[a-z]+@[a-z]+\\.[a-z]{2,}$"`
+		Tags    []string      `copy:"tags" validate:"max=2"`
+		Timeout time.Duration `copy:"timeout" validate:"min=1s"`
+		Rate    *float64      `copy:"rate" validate:"max=1.5"`
+		TLS     tls           `copy:"tls"`
+	}
+
+	valid := map[string]interface{}{
+		"name":    "John",
+		"port":    8080,
+		"mode":    "dev",
+		"email":   "john@example.com",
+		"tags":    []interface{}{"a"},
+		"timeout": "5s",
+		"tls":     map[string]interface{}{"cert": "cert.pem"},
+	}
+
+	t.Run("test valid", func(t *testing.T) {
+		testOut := testStruct{}
+
+		if err := Decode(valid, &testOut, "copy", DecoderValidate); err != nil {
+			t.Errorf("Decode() error = %v", err)
+		}
+	})
+
+	t.Run("test invalid", func(t *testing.T) {
+		testIn := map[string]interface{}{
+			"name":    "J",
+			"port":    70000,
+			"mode":    "test",
+			"email":   "not an email",
+			"tags":    []interface{}{"a", "b", "c"},
+			"timeout": "10ms",
+			"rate":    2.5,
+		}
+		testOut := testStruct{}
+
+		err := Decode(testIn, &testOut, "copy", DecoderValidate|DecoderCollectErrors)
+		if !errors.Is(err, ErrorValidation) {
+			t.Fatalf("Decode() error = %v, want %v", err, ErrorValidation)
+		}
+
+		var paths []string
+		for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+			paths = append(paths, e.(*DecodeError).Path)
+		}
+		sort.Strings(paths)
+
+		want := []string{"email", "mode", "name", "port", "rate", "tags", "timeout", "tls.cert"}
+		if !reflect.DeepEqual(paths, want) {
+			t.Errorf("Decode() paths = %v, want %v", paths, want)
+		}
+	})
+
+	t.Run("test nested required", func(t *testing.T) {
+		testIn := map[string]interface{}{"tls": map[string]interface{}{}}
+		testOut := struct {
+			TLS tls `copy:"tls"`
+		}{}
+
+		err := Decode(testIn, &testOut, "copy", DecoderValidate)
+
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Path != "tls.cert" {
+			t.Errorf("Decode() error = %v", err)
+		}
+	})
+
+	t.Run("test validation disabled", func(t *testing.T) {
+		testOut := testStruct{}
+
+		if err := Decode(map[string]interface{}{"port": 0}, &testOut, "copy", 0); err != nil {
+			t.Errorf("Decode() error = %v", err)
+		}
+	})
+}
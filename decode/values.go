@@ -0,0 +1,32 @@
+package decode
+
+import (
+	"net/http"
+	"net/url"
+)
+
+const formTag = "form"
+
+// FromValues decodes url.Values such as query parameters into destination.
+// Fields are named by the form tag or match the field name in any case or naming
+// convention, nested structs use dotted keys ("filter.name"). Several values of a
+// key fill a slice, a scalar field takes the first one.
+func FromValues(values url.Values, destination interface{}, opts ...Option) error {
+	d := NewDecoder(append([]Option{
+		WithTags(formTag),
+		WithFlags(DecoderWeaklyTyped | DecoderCaseInsensitive | DecoderMatchConventions),
+		WithKeySeparator("."),
+	}, opts...)...)
+
+	return d.Decode(map[string][]string(values), destination)
+}
+
+// FromRequest decodes the query parameters and the form body of r into destination
+// like FromValues. Body values take precedence over the query.
+func FromRequest(r *http.Request, destination interface{}, opts ...Option) error {
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+
+	return FromValues(r.Form, destination, opts...)
+}
@@ -0,0 +1,89 @@
+package decode
+
+import (
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFromValues(t *testing.T) {
+	type filter struct {
+		Name  string `form:"name"`
+		Since time.Time
+	}
+
+	type query struct {
+		Page     int      `form:"page" default:"1"`
+		PageSize int      `validate:"max=100"`
+		Tags     []string `form:"tag"`
+		IDs      []int    `form:"id"`
+		Active   bool     `form:"active"`
+		Filter   filter   `form:"filter"`
+	}
+
+	t.Run("test decode values", func(t *testing.T) {
+		values, err := url.ParseQuery("page=2&page_size=50&tag=a&tag=b&id=1&id=2&active=on&filter.name=x&filter.since=2024-01-02")
+		if err != nil {
+			t.Fatal(err)
+		}
+		testOut := query{}
+
+		if err := FromValues(values, &testOut); err != nil {
+			t.Fatalf("FromValues() error = %v", err)
+		}
+
+		want := query{
+			Page:     2,
+			PageSize: 50,
+			Tags:     []string{"a", "b"},
+			IDs:      []int{1, 2},
+			Active:   true,
+			Filter:   filter{Name: "x", Since: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		}
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("FromValues() = %+v, want %+v", testOut, want)
+		}
+	})
+
+	t.Run("test first value and defaults", func(t *testing.T) {
+		testOut := query{}
+
+		testIn := url.Values{"pageSize": {"10", "20"}, "tag": {}, "filter.since": {""}}
+		if err := FromValues(testIn, &testOut); err != nil {
+			t.Fatalf("FromValues() error = %v", err)
+		}
+
+		want := query{Page: 1, PageSize: 10, Tags: []string{}}
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("FromValues() = %+v, want %+v", testOut, want)
+		}
+	})
+
+	t.Run("test validate", func(t *testing.T) {
+		testOut := query{}
+
+		err := FromValues(url.Values{"PageSize": {"500"}}, &testOut, WithValidate())
+		if !errors.Is(err, ErrorValidation) {
+			t.Errorf("FromValues() error = %v, want %v", err, ErrorValidation)
+		}
+	})
+
+	t.Run("test request", func(t *testing.T) {
+		r := httptest.NewRequest("POST", "/items?page=3&tag=q", strings.NewReader("tag=body&active=true"))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		testOut := query{}
+
+		if err := FromRequest(r, &testOut); err != nil {
+			t.Fatalf("FromRequest() error = %v", err)
+		}
+
+		want := query{Page: 3, Tags: []string{"body", "q"}, Active: true}
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("FromRequest() = %+v, want %+v", testOut, want)
+		}
+	})
+}
@@ -0,0 +1,259 @@
+package decode
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Watcher reloads a config with a Loader when its files change and publishes every
+// version that decodes and validates. A failed reload keeps the previous version.
+type Watcher[T any] struct {
+	loader *Loader
+	config watchConfig
+
+	current atomic.Pointer[snapshot[T]]
+
+	reloadMu sync.Mutex // Serializes reloads so changes are queued in version order
+	stamps   map[string]fileStamp
+
+	subsMu sync.Mutex
+	subs   map[int]func(previous, current *T)
+	nextID int
+
+	notifyMu  sync.Mutex
+	pending   []change[T]
+	notifying bool
+}
+
+type change[T any] struct {
+	previous *T
+	current  *T
+}
+
+type snapshot[T any] struct {
+	value   *T
+	sources Sources
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+type watchConfig struct {
+	interval time.Duration
+	onError  func(error)
+}
+
+// WatchOption configures a Watcher.
+type WatchOption func(*watchConfig)
+
+// WithInterval sets how often config files are checked for changes, every second by default.
+// The interval must be positive.
+func WithInterval(interval time.Duration) WatchOption {
+	return func(c *watchConfig) {
+		c.interval = interval
+	}
+}
+
+// WithErrorHandler receives the errors of reloads triggered by file changes.
+func WithErrorHandler(onError func(error)) WatchOption {
+	return func(c *watchConfig) {
+		c.onError = onError
+	}
+}
+
+// Watch loads the config and polls the files of loader until ctx is done. The initial
+// load must succeed. If *T implements Validate() error, it is called on every version.
+//
+//	w, err := decode.Watch[Config](ctx, loader)
+//	cancel := w.Subscribe(func(previous, current *Config) { ... })
+//	cfg := w.Get()
+func Watch[T any](ctx context.Context, loader *Loader, opts ...WatchOption) (*Watcher[T], error) {
+	w := &Watcher[T]{
+		loader: loader,
+		config: watchConfig{interval: time.Second},
+		subs:   make(map[int]func(previous, current *T)),
+	}
+
+	for _, opt := range opts {
+		opt(&w.config)
+	}
+
+	if w.config.interval <= 0 {
+		return nil, fmt.Errorf("watch interval must be positive, got %v", w.config.interval)
+	}
+
+	w.stamps = w.stat()
+	if err := w.Reload(); err != nil {
+		return nil, err
+	}
+
+	go w.poll(ctx)
+
+	return w, nil
+}
+
+// Get returns the current config. It must not be modified.
+func (w *Watcher[T]) Get() *T {
+	return w.current.Load().value
+}
+
+// Sources returns the layer each field of the current config came from.
+func (w *Watcher[T]) Sources() Sources {
+	return w.current.Load().sources
+}
+
+// Subscribe calls fn with the previous and the new config after every successful
+// reload, one change at a time and in version order. fn may call Reload, the change
+// it publishes is passed to subscribers after fn returns. The returned function
+// cancels the subscription.
+func (w *Watcher[T]) Subscribe(fn func(previous, current *T)) func() {
+	w.subsMu.Lock()
+	defer w.subsMu.Unlock()
+
+	id := w.nextID
+	w.nextID++
+	w.subs[id] = fn
+
+	return func() {
+		w.subsMu.Lock()
+		defer w.subsMu.Unlock()
+
+		delete(w.subs, id)
+	}
+}
+
+// Reload loads the config into a new value and publishes it. On failure the
+// current config is kept and the error is returned. Subscribers are called outside
+// the reload lock; while another goroutine is notifying them, Reload leaves the
+// change to it and returns without waiting.
+func (w *Watcher[T]) Reload() error {
+	if err := w.publish(); err != nil {
+		return err
+	}
+
+	w.notify()
+
+	return nil
+}
+
+// publish loads and validates a new version, makes it current and queues the change.
+func (w *Watcher[T]) publish() error {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	value := new(T)
+
+	sources, err := w.loader.Load(value)
+	if err != nil {
+		return err
+	}
+
+	if v, ok := interface{}(value).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return err
+		}
+	}
+
+	previous := w.current.Swap(&snapshot[T]{value: value, sources: sources})
+	if previous == nil {
+		return nil
+	}
+
+	w.notifyMu.Lock()
+	w.pending = append(w.pending, change[T]{previous: previous.value, current: value})
+	w.notifyMu.Unlock()
+
+	return nil
+}
+
+// notify passes the queued changes to the subscribers in order. Only one goroutine
+// notifies at a time, changes queued meanwhile are passed on by it as well.
+func (w *Watcher[T]) notify() {
+	w.notifyMu.Lock()
+	if w.notifying {
+		w.notifyMu.Unlock()
+		return
+	}
+	w.notifying = true
+
+	for len(w.pending) > 0 {
+		next := w.pending[0]
+		w.pending = w.pending[1:]
+		w.notifyMu.Unlock()
+
+		for _, fn := range w.subscribers() {
+			fn(next.previous, next.current)
+		}
+
+		w.notifyMu.Lock()
+	}
+
+	w.notifying = false
+	w.notifyMu.Unlock()
+}
+
+func (w *Watcher[T]) subscribers() []func(previous, current *T) {
+	w.subsMu.Lock()
+	defer w.subsMu.Unlock()
+
+	subs := make([]func(previous, current *T), 0, len(w.subs))
+	for _, fn := range w.subs {
+		subs = append(subs, fn)
+	}
+
+	return subs
+}
+
+func (w *Watcher[T]) poll(ctx context.Context) {
+	ticker := time.NewTicker(w.config.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			stamps := w.stat()
+			if !changed(w.stamps, stamps) {
+				continue
+			}
+			w.stamps = stamps
+
+			if err := w.Reload(); err != nil && w.config.onError != nil {
+				w.config.onError(err)
+			}
+		}
+	}
+}
+
+// stat returns the modification time and size of the loader files, zero for missing ones.
+func (w *Watcher[T]) stat() map[string]fileStamp {
+	stamps := make(map[string]fileStamp, len(w.loader.files))
+
+	for _, file := range w.loader.files {
+		if info, err := os.Stat(file.path); err == nil {
+			stamps[file.path] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		} else {
+			stamps[file.path] = fileStamp{}
+		}
+	}
+
+	return stamps
+}
+
+func changed(old map[string]fileStamp, current map[string]fileStamp) bool {
+	for path, stamp := range current {
+		if prev := old[path]; !prev.modTime.Equal(stamp.modTime) || prev.size != stamp.size {
+			return true
+		}
+	}
+
+	return false
+}
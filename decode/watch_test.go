@@ -0,0 +1,183 @@
+package decode
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type reloadConfig struct {
+	Name string `json:"name" validate:"required"`
+	Port int    `json:"port"`
+}
+
+func (c *reloadConfig) Validate() error {
+	if c.Port < 0 {
+		return errors.New("negative port")
+	}
+
+	return nil
+}
+
+func TestWatch(t *testing.T) {
+	path := writeFile(t, "config.json", `{"name": "api", "port": 1}`)
+	loader := NewLoader(WithFile(path), WithDecodeOptions(WithValidate()))
+
+	modified := time.Now()
+	update := func(content string) {
+		t.Helper()
+
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		// Make the change visible on file systems with a coarse modification time.
+		modified = modified.Add(time.Second)
+		if err := os.Chtimes(tmp, modified, modified); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.Rename(tmp, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errs := make(chan error, 10)
+	w, err := Watch[reloadConfig](ctx, loader, WithInterval(5*time.Millisecond), WithErrorHandler(func(err error) {
+		errs <- err
+	}))
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	if got := *w.Get(); got != (reloadConfig{Name: "api", Port: 1}) {
+		t.Fatalf("Get() = %+v, want initial config", got)
+	}
+
+	changes := make(chan [2]reloadConfig, 10)
+	unsubscribe := w.Subscribe(func(previous, current *reloadConfig) {
+		changes <- [2]reloadConfig{*previous, *current}
+	})
+
+	t.Run("test publish change", func(t *testing.T) {
+		update(`{"name": "api", "port": 2}`)
+
+		select {
+		case change := <-changes:
+			if change[0].Port != 1 || change[1].Port != 2 {
+				t.Errorf("Subscribe() change = %+v, want port 1 -> 2", change)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Subscribe() no change published")
+		}
+
+		if w.Get().Port != 2 || w.Sources()["Port"] != "file:"+path {
+			t.Errorf("Get() = %+v, Sources() = %v", w.Get(), w.Sources())
+		}
+	})
+
+	t.Run("test keep previous on failure", func(t *testing.T) {
+		for _, tt := range []struct {
+			content string
+			want    error
+		}{
+			{content: `{"name": "api", "port": "x"}`, want: ErrorTypeMismatch},
+			{content: `{"port": 3}`, want: ErrorValidation},
+			{content: `{"name": "api", "port": -1}`},
+		} {
+			update(tt.content)
+
+			select {
+			case err := <-errs:
+				if tt.want != nil && !errors.Is(err, tt.want) {
+					t.Errorf("reload error = %v, want %v", err, tt.want)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("reload of %s reported no error", tt.content)
+			}
+
+			if w.Get().Port != 2 {
+				t.Errorf("Get() = %+v, want previous config", w.Get())
+			}
+		}
+
+		if len(changes) != 0 {
+			t.Errorf("Subscribe() got %d changes, want none", len(changes))
+		}
+	})
+
+	t.Run("test unsubscribe", func(t *testing.T) {
+		unsubscribe()
+
+		update(`{"name": "api", "port": 4}`)
+		if err := w.Reload(); err != nil {
+			t.Fatalf("Reload() error = %v", err)
+		}
+
+		if w.Get().Port != 4 || len(changes) != 0 {
+			t.Errorf("Get() = %+v, changes = %d, want port 4 without notifications", w.Get(), len(changes))
+		}
+	})
+
+	t.Run("test reload from subscriber", func(t *testing.T) {
+		var reloaded atomic.Bool
+		nested := make(chan [2]reloadConfig, 10)
+
+		cancelNested := w.Subscribe(func(previous, current *reloadConfig) {
+			if current.Port == 5 && reloaded.CompareAndSwap(false, true) {
+				if err := w.Reload(); err != nil {
+					t.Errorf("Reload() error = %v", err)
+				}
+			} else if reloaded.Load() {
+				nested <- [2]reloadConfig{*previous, *current}
+			}
+		})
+		defer cancelNested()
+
+		update(`{"name": "api", "port": 5}`)
+
+		done := make(chan error, 1)
+		go func() { done <- w.Reload() }()
+
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("Reload() error = %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Reload() from a subscriber deadlocked")
+		}
+
+		select {
+		case change := <-nested:
+			if change[0].Port != 5 || change[1].Port != 5 {
+				t.Errorf("Subscribe() change = %+v, want port 5 -> 5", change)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Subscribe() change of the nested reload not published")
+		}
+	})
+
+	t.Run("test initial load failure", func(t *testing.T) {
+		bad := NewLoader(WithFile(writeFile(t, "bad.json", `{"port": 1}`)), WithDecodeOptions(WithValidate()))
+
+		if _, err := Watch[reloadConfig](ctx, bad); !errors.Is(err, ErrorValidation) {
+			t.Errorf("Watch() error = %v, want %v", err, ErrorValidation)
+		}
+	})
+
+	t.Run("test invalid interval", func(t *testing.T) {
+		for _, interval := range []time.Duration{0, -time.Second} {
+			if _, err := Watch[reloadConfig](ctx, loader, WithInterval(interval)); err == nil {
+				t.Errorf("Watch() with interval %v error = nil", interval)
+			}
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package decode
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// decodeWeak applies the DecoderWeaklyTyped conversions. It reports whether the value was handled.
+func (d *decoder) decodeWeak(source reflect.Value, destination reflect.Value, path string) (bool, error) {
+	if d.flag&DecoderWeaklyTyped == 0 || destination.Kind() == reflect.Interface {
+		return false, nil
+	}
+
+	if source.Kind() == reflect.String {
+		str := strings.TrimSpace(source.String())
+
+		switch {
+		case destination.Kind() == reflect.Bool:
+			b, err := parseWeakBool(str)
+			if err != nil {
+				return true, d.fail(newDecodeError(path, source, destination.Type(), ErrorTypeMismatch, err))
+			}
+
+			destination.SetBool(b)
+			return true, nil
+
+		case destination.Kind() == reflect.Slice && destination.Type().Elem().Kind() == reflect.Uint8:
+			destination.SetBytes([]byte(source.String()))
+			return true, nil
+
+		case destination.Kind() == reflect.Slice || destination.Kind() == reflect.Array:
+			parts := strings.Split(str, ",")
+			for i := range parts {
+				parts[i] = strings.TrimSpace(parts[i])
+			}
+
+			return true, d.copySlice(reflect.ValueOf(parts), destination, path)
+		}
+	}
+
+	if (destination.Kind() == reflect.Slice || destination.Kind() == reflect.Array) &&
+		source.Kind() != reflect.Slice && source.Kind() != reflect.Array {
+		list := reflect.MakeSlice(reflect.SliceOf(source.Type()), 1, 1)
+		list.Index(0).Set(source)
+
+		return true, d.copySlice(list, destination, path)
+	}
+
+	return false, nil
+}
+
+// weakEmpty reports whether source is an empty string that decodes into the zero
+// value of destination when DecoderWeaklyTyped is set. It is checked before time
+// and unmarshaler conversions, so "" gives a zero time.Time or a nil *time.Time.
+func (d *decoder) weakEmpty(source reflect.Value, destination reflect.Value) bool {
+	if d.flag&DecoderWeaklyTyped == 0 || source.Kind() != reflect.String || strings.TrimSpace(source.String()) != "" {
+		return false
+	}
+
+	t := destination.Type()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Kind() != reflect.String && t.Kind() != reflect.Interface
+}
+
+// collapseList decodes the first element of a list into a scalar destination when
+// DecoderWeaklyTyped is set, e.g. for url.Values. It reports whether the value was handled.
+func (d *decoder) collapseList(source reflect.Value, destination reflect.Value, path string) (bool, error) {
+	if d.flag&DecoderWeaklyTyped == 0 || !isList(source) || !collapsible(destination.Type()) {
+		return false, nil
+	}
+
+	if source.Len() == 0 {
+		destination.Set(reflect.Zero(destination.Type()))
+		return true, nil
+	}
+
+	return true, d.copyValues(source.Index(0), destination, path)
+}
+
+// isList reports whether v is a slice or array other than a byte slice.
+func isList(v reflect.Value) bool {
+	return (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8
+}
+
+// collapsible reports whether a list decodes into t by its first element, as
+// url.Values.Get does: t is a scalar, time.Time or a TextUnmarshaler.
+func collapsible(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Interface:
+		return false
+
+	case reflect.Struct:
+		return t == timeType || reflect.PointerTo(t).Implements(textUnmarshalerType)
+	}
+
+	return true
+}
+
+func parseWeakBool(str string) (bool, error) {
+	switch strings.ToLower(str) {
+	case "1", "t", "true", "y", "yes", "on":
+		return true, nil
+	case "0", "f", "false", "n", "no", "off":
+		return false, nil
+	}
+
+	return false, fmt.Errorf("invalid boolean %q", str)
+}
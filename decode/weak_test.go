@@ -0,0 +1,81 @@
+package decode
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDecodeWeaklyTyped(t *testing.T) {
+	type testStruct struct {
+		Hosts   []string      `copy:"hosts"`
+		Ports   []int         `copy:"ports"`
+		Single  []int         `copy:"single"`
+		Debug   bool          `copy:"debug"`
+		Verbose bool          `copy:"verbose"`
+		Quiet   bool          `copy:"quiet"`
+		Port    int           `copy:"port"`
+		Timeout time.Duration `copy:"timeout"`
+		Data    []byte        `copy:"data"`
+		Pair    [2]string     `copy:"pair"`
+		Since   time.Time     `copy:"since"`
+		Until   *time.Time    `copy:"until"`
+	}
+
+	t.Run("test weakly typed", func(t *testing.T) {
+		testIn := map[string]interface{}{
+			"hosts":   "a.example.com, b.example.com",
+			"ports":   "80,443",
+			"single":  8080,
+			"debug":   "yes",
+			"verbose": "on",
+			"quiet":   "0",
+			"port":    "",
+			"timeout": "",
+			"data":    "raw",
+			"pair":    "x,y",
+			"since":   "",
+			"until":   " ",
+		}
+		until := time.Now()
+		testOut := testStruct{Port: 1, Since: until, Until: &until}
+
+		if err := Decode(testIn, &testOut, "copy", DecoderWeaklyTyped); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		want := testStruct{
+			Hosts:   []string{"a.example.com", "b.example.com"},
+			Ports:   []int{80, 443},
+			Single:  []int{8080},
+			Debug:   true,
+			Verbose: true,
+			Data:    []byte("raw"),
+			Pair:    [2]string{"x", "y"},
+		}
+
+		if !reflect.DeepEqual(testOut, want) {
+			t.Errorf("Decode() = %+v, want %+v", testOut, want)
+		}
+	})
+
+	t.Run("test invalid bool", func(t *testing.T) {
+		testOut := testStruct{}
+
+		err := Decode(map[string]interface{}{"debug": "maybe"}, &testOut, "copy", DecoderWeaklyTyped)
+
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || decodeErr.Path != "debug" {
+			t.Errorf("Decode() error = %v", err)
+		}
+	})
+
+	t.Run("test strict without flag", func(t *testing.T) {
+		testOut := testStruct{}
+
+		if err := Decode(map[string]interface{}{"debug": "yes"}, &testOut, "copy", 0); err == nil {
+			t.Errorf("Decode() error = nil")
+		}
+	})
+}